@@ -0,0 +1,319 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+const bundleTag = "submit"
+
+type bundlePackage struct {
+	path   string
+	prefix string
+	files  []*ast.File
+	pkg    *types.Package
+	info   *types.Info
+}
+
+type bundler struct {
+	ctx      build.Context
+	fset     *token.FileSet
+	std      types.Importer
+	packages map[string]*bundlePackage
+	order    []*bundlePackage
+	prefixes map[string]bool
+}
+
+func newBundler() *bundler {
+	ctx := build.Default
+	ctx.BuildTags = append(ctx.BuildTags, bundleTag)
+	ctx.CgoEnabled = false
+	return &bundler{
+		ctx:      ctx,
+		fset:     token.NewFileSet(),
+		std:      importer.Default(),
+		packages: map[string]*bundlePackage{},
+		prefixes: map[string]bool{},
+	}
+}
+
+func (b *bundler) Import(path string) (*types.Package, error) {
+	return b.ImportFrom(path, ".", 0)
+}
+
+func (b *bundler) ImportFrom(path, dir string, mode types.ImportMode) (*types.Package, error) {
+	if bp, ok := b.packages[path]; ok {
+		return bp.pkg, nil
+	}
+	pkg, err := b.ctx.Import(path, dir, build.FindOnly)
+	if err != nil {
+		return nil, err
+	}
+	if pkg.Goroot {
+		return b.std.Import(path)
+	}
+	return b.load(pkg.Dir, path).pkg, nil
+}
+
+func (b *bundler) load(dir, path string) *bundlePackage {
+	pkg, err := b.ctx.ImportDir(dir, 0)
+	if err != nil {
+		log.Fatalln("Error loading package:", err)
+	}
+
+	bp := &bundlePackage{path: path}
+	for _, name := range pkg.GoFiles {
+		f, err := parser.ParseFile(b.fset, dir+string(os.PathSeparator)+name, nil, 0)
+		if err != nil {
+			log.Fatalln("Error parsing file:", err)
+		}
+		bp.files = append(bp.files, f)
+	}
+	if pkg.Name != "main" {
+		bp.prefix = pkg.Name + "_"
+		for n := 2; b.prefixes[bp.prefix]; n++ {
+			bp.prefix = pkg.Name + strconv.Itoa(n) + "_"
+		}
+		b.prefixes[bp.prefix] = true
+	}
+	b.packages[path] = bp
+
+	bp.info = &types.Info{
+		Defs: map[*ast.Ident]types.Object{},
+		Uses: map[*ast.Ident]types.Object{},
+	}
+	conf := types.Config{Importer: b}
+	bp.pkg, err = conf.Check(path, b.fset, bp.files, bp.info)
+	if err != nil {
+		log.Fatalln("Error type checking package:", err)
+	}
+	b.order = append(b.order, bp)
+	return bp
+}
+
+func (b *bundler) inlined(pkg *types.Package) *bundlePackage {
+	if pkg == nil {
+		return nil
+	}
+	bp := b.packages[pkg.Path()]
+	if bp == nil || bp.prefix == "" {
+		return nil
+	}
+	return bp
+}
+
+func (b *bundler) rename(bp *bundlePackage) {
+	idents := map[*ast.Ident]types.Object{}
+	for ident, obj := range bp.info.Defs {
+		idents[ident] = obj
+	}
+	for ident, obj := range bp.info.Uses {
+		idents[ident] = obj
+	}
+	for ident, obj := range idents {
+		if obj == nil || ident.Name == "_" || ident.Name == "init" {
+			continue
+		}
+		owner := b.inlined(obj.Pkg())
+		if owner == nil {
+			continue
+		}
+		if obj.Parent() == obj.Pkg().Scope() {
+			ident.Name = owner.prefix + ident.Name
+		} else if v, ok := obj.(*types.Var); ok && v.Embedded() {
+			if named, ok := derefType(v.Type()).(*types.Named); ok && b.inlined(named.Obj().Pkg()) != nil {
+				ident.Name = b.inlined(named.Obj().Pkg()).prefix + ident.Name
+			}
+		}
+	}
+
+	for _, f := range bp.files {
+		rewriteExprs(f, func(expr ast.Expr) ast.Expr {
+			sel, ok := expr.(*ast.SelectorExpr)
+			if !ok {
+				return expr
+			}
+			x, ok := sel.X.(*ast.Ident)
+			if !ok {
+				return expr
+			}
+			if _, ok := bp.info.Uses[x].(*types.PkgName); !ok {
+				return expr
+			}
+			if owner := b.inlined(bp.info.Uses[sel.Sel].Pkg()); owner != nil {
+				return ast.NewIdent(sel.Sel.Name)
+			}
+			return expr
+		})
+	}
+}
+
+func derefType(t types.Type) types.Type {
+	if p, ok := t.(*types.Pointer); ok {
+		return p.Elem()
+	}
+	return t
+}
+
+var exprType = reflect.TypeOf((*ast.Expr)(nil)).Elem()
+
+func rewriteExprs(node interface{}, f func(ast.Expr) ast.Expr) {
+	rewriteValue(reflect.ValueOf(node), f)
+}
+
+func rewriteValue(v reflect.Value, f func(ast.Expr) ast.Expr) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+			rewriteValue(v.Elem(), f)
+		}
+	case reflect.Interface:
+		if !v.IsNil() {
+			rewriteValue(v.Elem(), f)
+		}
+	case reflect.Slice:
+		for j := 0; j < v.Len(); j++ {
+			rewriteField(v.Index(j), f)
+		}
+	case reflect.Struct:
+		for j := 0; j < v.NumField(); j++ {
+			if v.Type().Field(j).PkgPath == "" {
+				rewriteField(v.Field(j), f)
+			}
+		}
+	}
+}
+
+func rewriteField(v reflect.Value, f func(ast.Expr) ast.Expr) {
+	if v.Type() == exprType && !v.IsNil() {
+		v.Set(reflect.ValueOf(f(v.Interface().(ast.Expr))))
+	}
+	if _, ok := v.Interface().(*ast.Object); ok {
+		return
+	}
+	if _, ok := v.Interface().(*ast.Scope); ok {
+		return
+	}
+	rewriteValue(v, f)
+}
+
+func (b *bundler) importSpecs(names map[string]string) []ast.Spec {
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return names[sorted[i]] < names[sorted[j]]
+	})
+	specs := []ast.Spec{}
+	for _, name := range sorted {
+		spec := &ast.ImportSpec{Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(names[name])}}
+		if pkg, _ := b.std.Import(names[name]); pkg.Name() != name {
+			spec.Name = ast.NewIdent(name)
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+func keptDecl(decl ast.Decl, kept map[ast.Node]bool) ast.Decl {
+	gen, ok := decl.(*ast.GenDecl)
+	if !ok {
+		if kept[decl] {
+			return decl
+		}
+		return nil
+	}
+	if gen.Tok == token.IMPORT {
+		return nil
+	}
+	if gen.Tok == token.CONST {
+		if kept[gen] {
+			return gen
+		}
+		return nil
+	}
+
+	specs := []ast.Spec{}
+	for _, spec := range gen.Specs {
+		if kept[spec] {
+			specs = append(specs, spec)
+		}
+	}
+	if len(specs) == 0 {
+		return nil
+	}
+	if len(specs) == 1 && gen.Lparen.IsValid() {
+		gen.Lparen, gen.Rparen = token.NoPos, token.NoPos
+	}
+	gen.Specs = specs
+	return gen
+}
+
+func bundle(dir string) []byte {
+	b := newBundler()
+	main := b.load(dir, "main")
+	if main.pkg.Name() != "main" {
+		log.Fatalln("Bundled package must be main, not", main.pkg.Name())
+	}
+	p := newPruner(b)
+	kept := p.prune()
+	for _, bp := range b.order {
+		b.rename(bp)
+	}
+
+	merged := &ast.File{Name: ast.NewIdent("main")}
+	merged.Decls = append(merged.Decls, &ast.GenDecl{Tok: token.IMPORT, Lparen: 1, Specs: b.importSpecs(p.imports)})
+	for _, bp := range b.order {
+		for _, f := range bp.files {
+			for _, decl := range f.Decls {
+				if decl := keptDecl(decl, kept); decl != nil {
+					merged.Decls = append(merged.Decls, decl)
+				}
+			}
+		}
+	}
+
+	buffer := &bytes.Buffer{}
+	buffer.WriteString("// Code generated by codejam bundle. DO NOT EDIT.\n\n")
+	if err := format.Node(buffer, b.fset, merged); err != nil {
+		log.Fatalln("Error printing bundle:", err)
+	}
+	src, err := format.Source(buffer.Bytes())
+	if err != nil {
+		log.Fatalln("Error formatting bundle:", err)
+	}
+	return src
+}
+
+func bundleCommand(args []string) {
+	flags := flag.NewFlagSet("bundle", flag.ExitOnError)
+	outputFn := flags.String("o", "", "write bundle to file instead of stdout")
+	flags.Parse(args)
+
+	dir := "."
+	if flags.NArg() > 0 {
+		dir = flags.Arg(0)
+	}
+	src := bundle(dir)
+	if *outputFn == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := ioutil.WriteFile(*outputFn, src, 0644); err != nil {
+		log.Fatalln("Error writing bundle:", err)
+	}
+}
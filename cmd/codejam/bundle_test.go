@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const maxBundleSize = 80 << 10
+
+func TestBundle(t *testing.T) {
+	src := bundle("../../example")
+	assert.NotContains(t, string(src), "codejam-go")
+	assert.Contains(t, string(src), "io_TestCases(testCase)")
+	assert.True(t, len(src) < maxBundleSize, "bundle has %d bytes", len(src))
+	for _, pkg := range []string{"net/http", "os/exec", "os/signal", "runtime/pprof", "testing", "html/template", "image/gif"} {
+		assert.NotContains(t, string(src), `"`+pkg+`"`)
+	}
+
+	assert.Equal(t, "Case #1: 5\nCase #2: 7\n", runBundle(t, src, "2\n5\n7\n"))
+}
+
+func TestBundlePrune(t *testing.T) {
+	src := bundle("testdata/prune")
+	assert.NotContains(t, string(src), "perimeter")
+	assert.NotContains(t, string(src), "unused")
+	assert.NotContains(t, string(src), "integer_Min")
+	assert.Equal(t, "9 blue 2\n", runBundle(t, src, ""))
+}
+
+func runBundle(t *testing.T, src []byte, input string) string {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not installed")
+	}
+	dir, err := ioutil.TempDir("", "codejam")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "main.go"), src, 0644))
+
+	cmd := exec.Command("go", "run", "main.go")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GO111MODULE=off")
+	cmd.Stdin = strings.NewReader(input)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	assert.NoError(t, err, stderr.String())
+	return string(out)
+}
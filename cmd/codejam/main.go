@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+)
+
+type command struct {
+	usage string
+	run   func(args []string)
+}
+
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"run": {
+			usage: "[problems], solve input files of problems in the manifest, all if none",
+			run:   runCommand,
+		},
+		"bundle": {
+			usage: "[-o file.go] [dir], write the solution in dir and all non standard packages it uses as a single main.go",
+			run:   bundleCommand,
+		},
+		"new": {
+			usage: "round/problem, create a solution with sample input, brute force stub, test and Makefile",
+			run:   newCommand,
+		},
+		"validate": {
+			usage: "[problems], check input files of problems in the manifest against their validators",
+			run:   validateCommand,
+		},
+	}
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %s %s\n", name, commands[name].usage)
+	}
+}
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+	cmd.run(os.Args[2:])
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const defaultManifestFn = "problems.yaml"
+
+type problem struct {
+	name       string
+	entrypoint string
+	inputs     string
+	correct    string
+	args       []string
+}
+
+func parseValue(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) > 0 && (value[0] == '"' || value[0] == '\'') {
+		if len(value) < 2 || value[len(value)-1] != value[0] {
+			log.Fatalln("Invalid quoted value in manifest:", value)
+		}
+		if value[0] == '\'' {
+			return value[1 : len(value)-1]
+		}
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			log.Fatalln("Invalid quoted value in manifest:", value)
+		}
+		return unquoted
+	}
+	if comment := strings.Index(value, " #"); comment >= 0 {
+		value = strings.TrimSpace(value[:comment])
+	}
+	return value
+}
+
+func parseManifest(data string) map[string]*problem {
+	problems := map[string]*problem{}
+	var current *problem
+	for n, line := range strings.Split(data, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed[0] == '#' || trimmed == "---" {
+			continue
+		}
+		sep := strings.IndexByte(trimmed, ':')
+		if sep < 0 {
+			log.Fatalf("Invalid manifest line %d, expected key: value: %s\n", n+1, line)
+		}
+		key, value := strings.TrimSpace(trimmed[:sep]), parseValue(trimmed[sep+1:])
+
+		if line[0] != ' ' && line[0] != '\t' {
+			if value != "" {
+				log.Fatalf("Invalid manifest line %d, expected problem name followed by indented fields: %s\n", n+1, line)
+			}
+			current = &problem{name: key, entrypoint: "./" + strings.ToLower(key)}
+			problems[key] = current
+			continue
+		}
+		if current == nil {
+			log.Fatalf("Invalid manifest line %d, field outside of problem: %s\n", n+1, line)
+		}
+		switch key {
+		case "entrypoint":
+			current.entrypoint = value
+		case "inputs":
+			current.inputs = value
+		case "correct":
+			current.correct = value
+		case "args":
+			args, err := splitArgs(value)
+			if err != nil {
+				log.Fatalf("Invalid manifest line %d, %v: %s\n", n+1, err, line)
+			}
+			current.args = args
+		default:
+			log.Fatalf("Unknown manifest field %q of problem %s\n", key, current.name)
+		}
+	}
+	return problems
+}
+
+func splitArgs(line string) ([]string, error) {
+	args := []string{}
+	arg := &strings.Builder{}
+	inArg := false
+	var quote rune
+	escaped := false
+	for _, c := range line {
+		switch {
+		case escaped:
+			arg.WriteRune(c)
+			escaped = false
+		case c == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			arg.WriteRune(c)
+		case c == '\'' || c == '"':
+			quote = c
+			inArg = true
+		case c == ' ' || c == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(c)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, errors.New("unterminated quote or escape")
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+func readManifest(fn string) map[string]*problem {
+	data, err := ioutil.ReadFile(fn)
+	if err != nil {
+		log.Fatalln("Error reading manifest:", err)
+	}
+	return parseManifest(string(data))
+}
+
+func selectProblems(problems map[string]*problem, names []string) []*problem {
+	if len(names) == 0 {
+		for name := range problems {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	selected := make([]*problem, 0, len(names))
+	for _, name := range names {
+		p, ok := problems[name]
+		if !ok {
+			log.Fatalln("Unknown problem:", name)
+		}
+		selected = append(selected, p)
+	}
+	return selected
+}
+
+func (p *problem) inputPattern() string {
+	inputs := p.inputs
+	if inputs == "" {
+		inputs = p.entrypoint
+	}
+	if info, err := os.Stat(inputs); err == nil && info.IsDir() {
+		return filepath.Join(inputs, "*.in")
+	}
+	return inputs
+}
+
+func (p *problem) command(extra ...string) []string {
+	args := []string{"run", p.entrypoint}
+	if p.correct != "" {
+		args = append(args, "-correct", p.correct)
+	}
+	args = append(args, p.args...)
+	args = append(args, extra...)
+	return append(args, p.inputPattern())
+}
+
+func runProblems(args []string, extra ...string) {
+	flags := flag.NewFlagSet("codejam", flag.ExitOnError)
+	manifestFn := flags.String("manifest", defaultManifestFn, "problem manifest file")
+	flags.Parse(args)
+
+	failed := []string{}
+	for _, p := range selectProblems(readManifest(*manifestFn), flags.Args()) {
+		log.Println("Problem", p.name)
+		cmd := exec.Command("go", p.command(extra...)...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			failed = append(failed, p.name)
+		}
+	}
+	if len(failed) > 0 {
+		log.Fatalln("Failed problems:", strings.Join(failed, ", "))
+	}
+}
+
+func runCommand(args []string) {
+	runProblems(args)
+}
+
+func validateCommand(args []string) {
+	runProblems(args, "-validate")
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseManifest(t *testing.T) {
+	problems := parseManifest(`# round 1A
+A:
+  entrypoint: ./pylons
+  inputs: "pylons/tests"
+  correct: .ans # judge files
+  args: -tolerance 1e-6
+B:
+`)
+	assert.Equal(t, &problem{
+		name:       "A",
+		entrypoint: "./pylons",
+		inputs:     "pylons/tests",
+		correct:    ".ans",
+		args:       []string{"-tolerance", "1e-6"},
+	}, problems["A"])
+	assert.Equal(t, &problem{name: "B", entrypoint: "./b"}, problems["B"])
+
+	selected := selectProblems(problems, nil)
+	assert.Equal(t, "A", selected[0].name)
+	assert.Equal(t, "B", selected[1].name)
+
+	assert.Equal(t, []string{"run", "./pylons", "-correct", ".ans", "-tolerance", "1e-6", "-validate", "pylons/tests"}, problems["A"].command("-validate"))
+}
+
+func TestManifestQuotedArgs(t *testing.T) {
+	problems := parseManifest(`A:
+  args: -reference "python 'my brute.py'" -tolerance 1e-6
+`)
+	assert.Equal(t, []string{"-reference", "python 'my brute.py'", "-tolerance", "1e-6"}, problems["A"].args)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+)
+
+var scaffoldFiles = []struct {
+	name    string
+	content string
+}{
+	{"main.go", `package main
+
+import (
+	"github.com/matematik7/codejam-go/io"
+)
+
+func main() {
+	io.TestCases(testCase, io.WithBrute(brute))
+}
+
+func testCase(input *io.Input, output *io.Output) {
+	n := input.Int()
+	output.Print(n)
+}
+`},
+	{"brute.go", `package main
+
+import (
+	"github.com/matematik7/codejam-go/io"
+)
+
+func brute(input *io.Input, output *io.Output) {
+	n := input.Int()
+	output.Print(n)
+}
+`},
+	{"main_test.go", `package main
+
+import (
+	"testing"
+
+	"github.com/matematik7/codejam-go/io"
+)
+
+func TestSamples(t *testing.T) {
+	io.GoTest(t, testCase, ".")
+}
+`},
+	{"sample.in", `2
+1
+2
+`},
+	{"sample.correct", `Case #1: 1
+Case #2: 2
+`},
+	{"Makefile", `.PHONY: build sample run test
+
+build:
+	go build -o solution .
+
+sample: build
+	./solution sample.in
+
+run: build
+	./solution *.in
+
+test:
+	go test .
+`},
+}
+
+func newCommand(args []string) {
+	if len(args) != 1 {
+		log.Fatalln("Usage: codejam new round/problem")
+	}
+	dir := filepath.Clean(args[0])
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Fatalln("Error creating problem directory:", err)
+	}
+
+	for _, file := range scaffoldFiles {
+		fn := filepath.Join(dir, file.name)
+		f, err := os.OpenFile(fn, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			log.Println("Skipping existing", fn)
+			continue
+		}
+		if err != nil {
+			log.Fatalln("Error creating file:", err)
+		}
+		_, err = f.WriteString(file.content)
+		f.Close()
+		if err != nil {
+			log.Fatalln("Error writing file:", err)
+		}
+		log.Println("Created", fn)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "codejam")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	problemDir := filepath.Join(dir, "round1a", "a")
+	assert.NoError(t, os.MkdirAll(problemDir, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(problemDir, "main.go"), []byte("existing"), 0644))
+
+	newCommand([]string{problemDir})
+
+	for _, file := range scaffoldFiles {
+		_, err := os.Stat(filepath.Join(problemDir, file.name))
+		assert.NoError(t, err, file.name)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(problemDir, "main.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, "existing", string(data))
+}
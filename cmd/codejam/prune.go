@@ -0,0 +1,187 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"log"
+)
+
+type declNode struct {
+	node ast.Node
+	bp   *bundlePackage
+	recv types.Object
+	name string
+	kept bool
+}
+
+type pruner struct {
+	b         *bundler
+	nodes     map[types.Object]*declNode
+	methods   []*declNode
+	queue     []*declNode
+	usedNames map[string]bool
+	std       map[*types.Package]bool
+	imports   map[string]string
+}
+
+func newPruner(b *bundler) *pruner {
+	p := &pruner{
+		b:         b,
+		nodes:     map[types.Object]*declNode{},
+		usedNames: map[string]bool{"Error": true},
+		std:       map[*types.Package]bool{},
+		imports:   map[string]string{},
+	}
+	for _, bp := range b.order {
+		for _, f := range bp.files {
+			for _, decl := range f.Decls {
+				p.addDecl(bp, decl)
+			}
+		}
+	}
+	return p
+}
+
+func (p *pruner) addDecl(bp *bundlePackage, decl ast.Decl) {
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		obj := bp.info.Defs[decl.Name]
+		d := &declNode{node: decl, bp: bp, name: decl.Name.Name}
+		if recv := obj.Type().(*types.Signature).Recv(); recv != nil {
+			if named, ok := derefType(recv.Type()).(*types.Named); ok {
+				d.recv = named.Obj()
+			}
+			p.methods = append(p.methods, d)
+			return
+		}
+		p.nodes[obj] = d
+		if decl.Name.Name == "init" || bp.prefix == "" && decl.Name.Name == "main" {
+			p.use(d)
+		}
+	case *ast.GenDecl:
+		if decl.Tok == token.IMPORT {
+			return
+		}
+		if decl.Tok == token.CONST {
+			d := &declNode{node: decl, bp: bp}
+			for _, spec := range decl.Specs {
+				for _, name := range spec.(*ast.ValueSpec).Names {
+					p.nodes[bp.info.Defs[name]] = d
+				}
+			}
+			return
+		}
+		for _, spec := range decl.Specs {
+			d := &declNode{node: spec, bp: bp}
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				p.nodes[bp.info.Defs[spec.Name]] = d
+			case *ast.ValueSpec:
+				for _, name := range spec.Names {
+					if name.Name == "_" {
+						p.use(d)
+						continue
+					}
+					p.nodes[bp.info.Defs[name]] = d
+				}
+			}
+		}
+	}
+}
+
+func (p *pruner) use(d *declNode) {
+	if d.kept {
+		return
+	}
+	d.kept = true
+	p.queue = append(p.queue, d)
+}
+
+func (p *pruner) useNames(iface *types.Interface) {
+	for i := 0; i < iface.NumMethods(); i++ {
+		p.usedNames[iface.Method(i).Name()] = true
+	}
+}
+
+func (p *pruner) useStd(pkg *types.Package) {
+	if p.std[pkg] {
+		return
+	}
+	p.std[pkg] = true
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		if tn, ok := scope.Lookup(name).(*types.TypeName); ok {
+			if iface, ok := tn.Type().Underlying().(*types.Interface); ok {
+				p.useNames(iface)
+			}
+		}
+	}
+}
+
+func (p *pruner) walk(d *declNode) {
+	ast.Inspect(d.node, func(n ast.Node) bool {
+		if it, ok := n.(*ast.InterfaceType); ok {
+			for _, field := range it.Methods.List {
+				for _, name := range field.Names {
+					p.usedNames[name.Name] = true
+				}
+			}
+			return true
+		}
+		ident, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		switch obj := d.bp.info.Uses[ident].(type) {
+		case nil:
+		case *types.PkgName:
+			if _, ok := p.b.packages[obj.Imported().Path()]; !ok {
+				if other, ok := p.imports[obj.Name()]; ok && other != obj.Imported().Path() {
+					log.Fatalf("Import name %s is used for %s and %s\n", obj.Name(), other, obj.Imported().Path())
+				}
+				p.imports[obj.Name()] = obj.Imported().Path()
+				p.useStd(obj.Imported())
+			}
+		case *types.Func:
+			if obj.Type().(*types.Signature).Recv() != nil {
+				p.usedNames[obj.Name()] = true
+			}
+		case *types.TypeName:
+			if iface, ok := obj.Type().Underlying().(*types.Interface); ok {
+				p.useNames(iface)
+			}
+		}
+		if used := p.nodes[d.bp.info.Uses[ident]]; used != nil {
+			p.use(used)
+		}
+		return true
+	})
+}
+
+func (p *pruner) prune() map[ast.Node]bool {
+	for {
+		for len(p.queue) > 0 {
+			d := p.queue[0]
+			p.queue = p.queue[1:]
+			p.walk(d)
+		}
+		for _, m := range p.methods {
+			if recv := p.nodes[m.recv]; !m.kept && recv != nil && recv.kept && p.usedNames[m.name] {
+				p.use(m)
+			}
+		}
+		if len(p.queue) == 0 {
+			break
+		}
+	}
+
+	kept := map[ast.Node]bool{}
+	for _, d := range p.nodes {
+		kept[d.node] = d.kept
+	}
+	for _, m := range p.methods {
+		kept[m.node] = m.kept
+	}
+	return kept
+}
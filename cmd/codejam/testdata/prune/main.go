@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/matematik7/codejam-go/integer"
+)
+
+type shape interface {
+	area() int
+}
+
+type square struct {
+	side int
+}
+
+func (s square) area() int {
+	return s.side * s.side
+}
+
+func (s square) perimeter() int {
+	return 4 * s.side
+}
+
+type color int
+
+const (
+	red color = iota
+	green
+	blue
+)
+
+func (c color) String() string {
+	return [...]string{"red", "green", "blue"}[c]
+}
+
+func unused() int {
+	return integer.Min(1, 2)
+}
+
+func main() {
+	var s shape = square{3}
+	fmt.Println(s.area(), blue, integer.Max(1, 2))
+}
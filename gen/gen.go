@@ -0,0 +1,143 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"strconv"
+)
+
+const Lowercase = "abcdefghijklmnopqrstuvwxyz"
+
+type G struct {
+	w         io.Writer
+	r         *rand.Rand
+	seed      int64
+	cases     int
+	lineStart bool
+	buffer    *bytes.Buffer
+}
+
+func New(seed int64) *G {
+	buffer := &bytes.Buffer{}
+	return &G{
+		w:         buffer,
+		r:         rand.New(rand.NewSource(seed)),
+		seed:      seed,
+		lineStart: true,
+		buffer:    buffer,
+	}
+}
+
+func Generator(f func(g *G)) func(r *rand.Rand, w io.Writer) {
+	return func(r *rand.Rand, w io.Writer) {
+		g := &G{
+			w:         w,
+			r:         r,
+			lineStart: true,
+		}
+		f(g)
+		g.Line()
+	}
+}
+
+func (g *G) Rand() *rand.Rand {
+	return g.r
+}
+
+func (g *G) Seed() int64 {
+	return g.seed
+}
+
+func (g *G) Cases() int {
+	return g.cases
+}
+
+func (g *G) Case() {
+	g.Line()
+	g.cases++
+}
+
+func (g *G) Line() {
+	if !g.lineStart {
+		g.w.Write([]byte{'\n'})
+		g.lineStart = true
+	}
+}
+
+func (g *G) token(str string) {
+	if !g.lineStart {
+		g.w.Write([]byte{' '})
+	}
+	io.WriteString(g.w, str)
+	g.lineStart = false
+}
+
+func (g *G) Token(a interface{}) {
+	g.token(fmt.Sprint(a))
+}
+
+func (g *G) Int(lo, hi int) int {
+	a := lo + g.r.Intn(hi-lo+1)
+	g.token(strconv.Itoa(a))
+	return a
+}
+
+func (g *G) Float(lo, hi float64) float64 {
+	f := lo + g.r.Float64()*(hi-lo)
+	g.token(strconv.FormatFloat(f, 'f', -1, 64))
+	return f
+}
+
+func (g *G) String(n int, alphabet string) string {
+	data := make([]byte, n)
+	for i := range data {
+		data[i] = alphabet[g.r.Intn(len(alphabet))]
+	}
+	g.token(string(data))
+	return string(data)
+}
+
+func (g *G) line(as []int) {
+	g.Line()
+	for _, a := range as {
+		g.token(strconv.Itoa(a))
+	}
+	g.Line()
+}
+
+func (g *G) Ints(n, lo, hi int) []int {
+	as := make([]int, n)
+	for i := range as {
+		as[i] = lo + g.r.Intn(hi-lo+1)
+	}
+	g.line(as)
+	return as
+}
+
+func (g *G) Permutation(n int) []int {
+	as := g.r.Perm(n)
+	for i := range as {
+		as[i]++
+	}
+	g.line(as)
+	return as
+}
+
+func (g *G) WriteTo(w io.Writer) (int64, error) {
+	g.Line()
+	n, err := fmt.Fprintln(w, g.cases)
+	if err != nil {
+		return int64(n), err
+	}
+	m, err := w.Write(g.buffer.Bytes())
+	return int64(n + m), err
+}
+
+func (g *G) WriteFile(fn string) error {
+	buffer := &bytes.Buffer{}
+	g.WriteTo(buffer)
+	return ioutil.WriteFile(fn, buffer.Bytes(), 0644)
+}
@@ -0,0 +1,53 @@
+package gen
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	g := New(1)
+	for i := 0; i < 2; i++ {
+		g.Case()
+		n := g.Int(3, 5)
+		g.String(4, "ab")
+		p := g.Permutation(n)
+		sorted := append([]int{}, p...)
+		sort.Ints(sorted)
+		assert.Equal(t, []int{1, 2, 3, 4, 5}[:n], sorted)
+		as := g.Ints(n, -2, 2)
+		for _, a := range as {
+			assert.True(t, a >= -2 && a <= 2)
+		}
+	}
+
+	b := &bytes.Buffer{}
+	g.WriteTo(b)
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	assert.Equal(t, "2", lines[0])
+	assert.Equal(t, 7, len(lines))
+	assert.Equal(t, 2, len(strings.Fields(lines[1])))
+
+	other := New(1)
+	other.Case()
+	other.Int(3, 5)
+	assert.Equal(t, lines[1][:1], strings.Fields(other.buffer.String())[0])
+	assert.Equal(t, int64(1), other.Seed())
+}
+
+func TestGenerator(t *testing.T) {
+	f := Generator(func(g *G) {
+		g.Int(7, 7)
+		g.Token("x")
+	})
+
+	b := &bytes.Buffer{}
+	f(rand.New(rand.NewSource(1)), b)
+	f(rand.New(rand.NewSource(1)), b)
+	assert.Equal(t, "7 x\n7 x\n", b.String())
+}
@@ -0,0 +1,97 @@
+package graph
+
+import (
+	"container/heap"
+
+	"github.com/matematik7/codejam-go/integer"
+)
+
+type Edge struct {
+	To     int
+	Weight int
+}
+
+type Graph struct {
+	Adj      [][]Edge
+	Directed bool
+}
+
+func New(n int, directed bool) *Graph {
+	return &Graph{
+		Adj:      make([][]Edge, n),
+		Directed: directed,
+	}
+}
+
+func (g *Graph) Len() int {
+	return len(g.Adj)
+}
+
+func (g *Graph) AddEdge(from, to, weight int) {
+	g.Adj[from] = append(g.Adj[from], Edge{To: to, Weight: weight})
+	if !g.Directed {
+		g.Adj[to] = append(g.Adj[to], Edge{To: from, Weight: weight})
+	}
+}
+
+func (g *Graph) BFS(src int) []int {
+	dist := make([]int, g.Len())
+	for i := range dist {
+		dist[i] = -1
+	}
+	dist[src] = 0
+
+	queue := []int{src}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, e := range g.Adj[v] {
+			if dist[e.To] == -1 {
+				dist[e.To] = dist[v] + 1
+				queue = append(queue, e.To)
+			}
+		}
+	}
+	return dist
+}
+
+func (g *Graph) Dijkstra(src int) []int {
+	dist := make([]int, g.Len())
+	for i := range dist {
+		dist[i] = integer.MAX
+	}
+	dist[src] = 0
+
+	pq := &distQueue{{v: src, dist: 0}}
+	for pq.Len() > 0 {
+		item := heap.Pop(pq).(distItem)
+		if item.dist > dist[item.v] {
+			continue
+		}
+		for _, e := range g.Adj[item.v] {
+			if d := item.dist + e.Weight; d < dist[e.To] {
+				dist[e.To] = d
+				heap.Push(pq, distItem{v: e.To, dist: d})
+			}
+		}
+	}
+	return dist
+}
+
+type distItem struct {
+	v    int
+	dist int
+}
+
+type distQueue []distItem
+
+func (q distQueue) Len() int            { return len(q) }
+func (q distQueue) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q distQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *distQueue) Push(x interface{}) { *q = append(*q, x.(distItem)) }
+func (q *distQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,36 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/matematik7/codejam-go/integer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraph(t *testing.T) {
+	g := New(3, false)
+	g.AddEdge(0, 1, 5)
+	assert.Equal(t, 3, g.Len())
+	assert.Equal(t, []Edge{{To: 1, Weight: 5}}, g.Adj[0])
+	assert.Equal(t, []Edge{{To: 0, Weight: 5}}, g.Adj[1])
+
+	d := New(2, true)
+	d.AddEdge(0, 1, 1)
+	assert.Equal(t, 0, len(d.Adj[1]))
+}
+
+func TestBFS(t *testing.T) {
+	g := New(5, false)
+	g.AddEdge(0, 1, 1)
+	g.AddEdge(1, 2, 1)
+	g.AddEdge(0, 3, 1)
+	assert.Equal(t, []int{0, 1, 2, 1, -1}, g.BFS(0))
+}
+
+func TestDijkstra(t *testing.T) {
+	g := New(4, true)
+	g.AddEdge(0, 1, 4)
+	g.AddEdge(0, 2, 1)
+	g.AddEdge(2, 1, 2)
+	assert.Equal(t, []int{0, 3, 1, integer.MAX}, g.Dijkstra(0))
+}
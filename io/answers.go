@@ -0,0 +1,83 @@
+package io
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxAnswerLen = 32
+	answersShown = 5
+)
+
+type answerStats struct {
+	counts   map[string]int
+	others   int
+	numeric  int
+	min, max float64
+}
+
+func (as *answerStats) add(answer string) {
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return
+	}
+	if f, err := strconv.ParseFloat(answer, 64); err == nil {
+		if as.numeric == 0 || f < as.min {
+			as.min = f
+		}
+		if as.numeric == 0 || f > as.max {
+			as.max = f
+		}
+		as.numeric++
+	}
+	if len(answer) > maxAnswerLen || strings.ContainsRune(answer, '\n') {
+		as.others++
+		return
+	}
+	if as.counts == nil {
+		as.counts = map[string]int{}
+	}
+	as.counts[answer]++
+}
+
+func (as *answerStats) String() string {
+	if len(as.counts) == 0 && as.others == 0 {
+		return ""
+	}
+
+	answers := make([]string, 0, len(as.counts))
+	for answer := range as.counts {
+		answers = append(answers, answer)
+	}
+	sort.Slice(answers, func(i, j int) bool {
+		if as.counts[answers[i]] != as.counts[answers[j]] {
+			return as.counts[answers[i]] > as.counts[answers[j]]
+		}
+		return answers[i] < answers[j]
+	})
+
+	parts := []string{}
+	for i, answer := range answers {
+		if i == answersShown {
+			parts = append(parts, fmt.Sprintf("%d more distinct", len(answers)-answersShown))
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s %d", answer, as.counts[answer]))
+	}
+	if as.others > 0 {
+		parts = append(parts, fmt.Sprintf("%d long", as.others))
+	}
+	if as.numeric > 0 {
+		parts = append(parts, fmt.Sprintf("numeric min %g max %g", as.min, as.max))
+	}
+	return "Answers: " + strings.Join(parts, ", ")
+}
+
+func (s *summary) addAnswer(answer string) {
+	s.Lock()
+	defer s.Unlock()
+	s.answers.add(answer)
+}
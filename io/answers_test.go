@@ -0,0 +1,24 @@
+package io
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnswerStats(t *testing.T) {
+	as := &answerStats{}
+	assert.Equal(t, "", as.String())
+
+	for _, answer := range []string{"YES\n", " NO", "YES", "", strings.Repeat("x", 40), "a\nb"} {
+		as.add(answer)
+	}
+	assert.Equal(t, "Answers: YES 2, NO 1, 2 long", as.String())
+
+	as = &answerStats{}
+	for _, answer := range []string{"g", "f", "e", "d", "c", "b", "a", "c", "111", "-2.5"} {
+		as.add(answer)
+	}
+	assert.Equal(t, "Answers: c 2, -2.5 1, 111 1, a 1, b 1, 4 more distinct, numeric min -2.5 max 111", as.String())
+}
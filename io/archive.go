@@ -0,0 +1,47 @@
+package io
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const archiveTimeFormat = "20060102-150405"
+
+func (parser *Parser) archiveRun(inputFns []string, now time.Time) string {
+	if parser.archiveDir == "" {
+		return ""
+	}
+
+	dir := filepath.Join(parser.archiveDir, now.Format(archiveTimeFormat))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Fatalln("Error creating archive directory:", err)
+	}
+
+	for _, inputFn := range inputFns {
+		fileParser := *parser
+		fileParser.SetFn(inputFn)
+		data, err := ioutil.ReadFile(fileParser.outputFn)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			log.Fatalln("Error reading output file:", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, filepath.Base(fileParser.outputFn)), data, 0644); err != nil {
+			log.Fatalln("Error archiving output file:", err)
+		}
+	}
+
+	f, err := os.Create(filepath.Join(dir, "report.json"))
+	if err != nil {
+		log.Fatalln("Error creating archive report:", err)
+	}
+	defer f.Close()
+	if err := writeReportJSON(f, parser.summary.reportCases()); err != nil {
+		log.Fatalln("Error writing archive report:", err)
+	}
+	return dir
+}
@@ -0,0 +1,38 @@
+package io
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArchiveRun(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	inputFn := filepath.Join(dir, "A.in")
+	writeTestFile(t, inputFn, "2\n1\n2\n")
+
+	archiveDir := filepath.Join(dir, "runs")
+	parser := newParser(double, WithArgs(inputFn), WithNoProfile(), WithArchive(archiveDir))
+	parser.Run()
+
+	runs, err := ioutil.ReadDir(archiveDir)
+	assert.NoError(t, err)
+	if assert.Len(t, runs, 1) {
+		_, err := time.Parse(archiveTimeFormat, runs[0].Name())
+		assert.NoError(t, err)
+
+		out, err := ioutil.ReadFile(filepath.Join(archiveDir, runs[0].Name(), "A.out"))
+		assert.NoError(t, err)
+		assert.Equal(t, "Case #1: 2\nCase #2: 4\n", string(out))
+
+		report, err := ioutil.ReadFile(filepath.Join(archiveDir, runs[0].Name(), "report.json"))
+		assert.NoError(t, err)
+		assert.Contains(t, string(report), `"case": 2`)
+	}
+}
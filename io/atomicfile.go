@@ -0,0 +1,33 @@
+package io
+
+import (
+	"io/ioutil"
+	"os"
+)
+
+type atomicFile struct {
+	*os.File
+	fn string
+}
+
+func createAtomic(fn string) (*atomicFile, error) {
+	f, err := os.Create(fn + ".tmp")
+	if err != nil {
+		return nil, err
+	}
+	return &atomicFile{f, fn}, nil
+}
+
+func (af *atomicFile) commit() error {
+	if err := af.File.Close(); err != nil {
+		return err
+	}
+	return os.Rename(af.Name(), af.fn)
+}
+
+func writeFileAtomic(fn string, data []byte) error {
+	if err := ioutil.WriteFile(fn+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(fn+".tmp", fn)
+}
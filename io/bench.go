@@ -0,0 +1,121 @@
+package io
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+func (parser *Parser) benchFlags(flags *flag.FlagSet) {
+	flags.IntVar(&parser.benchRuns, "n", 5, "number of runs per input file")
+}
+
+func (parser *Parser) benchCommand(args []string) {
+	inputFns := parser.parseFlagSet(parser.newFlagSet("bench"), args)
+	if len(inputFns) == 0 {
+		inputFns = parser.inputFns
+	}
+	inputFns = parser.downloadInputs(expandGlobs(inputFns))
+	parser.benchFiles(inputFns, parser.benchRuns)
+}
+
+func (parser *Parser) benchFiles(inputFns []string, runs int) {
+	if len(inputFns) == 0 || runs < 1 {
+		log.Fatalln("You need to specify at least one input file and one run")
+	}
+
+	for _, inputFn := range inputFns {
+		input, err := readInput(inputFn)
+		if err != nil {
+			log.Fatalln("Error reading input file:", err)
+		}
+
+		durations := make([]int64, 0, runs)
+		caseDurations := map[int][]int64{}
+		caseNs := []int{}
+		for run := 0; run < runs; run++ {
+			startTime := time.Now()
+			_, results := parser.solve(parser.f, input)
+			durations = append(durations, time.Since(startTime).Nanoseconds())
+
+			for _, r := range results {
+				if _, ok := caseDurations[r.caseN]; !ok {
+					caseNs = append(caseNs, r.caseN)
+				}
+				caseDurations[r.caseN] = append(caseDurations[r.caseN], r.duration.Nanoseconds())
+			}
+		}
+
+		buffer := &bytes.Buffer{}
+		tw := tabwriter.NewWriter(buffer, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "Case\tMin\tMedian\tMax\tStddev\t")
+		for _, caseN := range caseNs {
+			s := newBenchStats(caseDurations[caseN])
+			fmt.Fprintf(tw, "%d\t%s\n", caseN, s)
+		}
+		tw.Flush()
+		log.Print(buffer.String())
+
+		s := newBenchStats(durations)
+		log.Printf("%s: runs: %d, min: %s, median: %s, max: %s, stddev: %s, throughput: %.2f cases/s\n",
+			inputFn,
+			runs,
+			formatDuration(s.min),
+			formatDuration(s.median),
+			formatDuration(s.max),
+			formatDuration(s.stddev),
+			float64(len(caseNs))/time.Duration(s.median).Seconds(),
+		)
+	}
+}
+
+type benchStats struct {
+	min    int64
+	median int64
+	max    int64
+	stddev int64
+}
+
+func newBenchStats(durations []int64) benchStats {
+	sorted := append([]int64{}, durations...)
+	sort.Sort(int64s(sorted))
+
+	var sum float64
+	for _, d := range sorted {
+		sum += float64(d)
+	}
+	mean := sum / float64(len(sorted))
+
+	var variance float64
+	for _, d := range sorted {
+		variance += (float64(d) - mean) * (float64(d) - mean)
+	}
+	variance /= float64(len(sorted))
+
+	return benchStats{
+		min:    sorted[0],
+		median: sorted[len(sorted)/2],
+		max:    sorted[len(sorted)-1],
+		stddev: int64(math.Sqrt(variance)),
+	}
+}
+
+func (s benchStats) String() string {
+	return fmt.Sprintf("%s\t%s\t%s\t%s\t",
+		formatDuration(s.min),
+		formatDuration(s.median),
+		formatDuration(s.max),
+		formatDuration(s.stddev),
+	)
+}
+
+type int64s []int64
+
+func (s int64s) Len() int           { return len(s) }
+func (s int64s) Less(i, j int) bool { return s[i] < s[j] }
+func (s int64s) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
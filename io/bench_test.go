@@ -0,0 +1,14 @@
+package io
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBenchStats(t *testing.T) {
+	durations := []int64{4, 2, 8, 6}
+	assert.Equal(t, benchStats{min: 2, median: 6, max: 8, stddev: 2}, newBenchStats(durations))
+	assert.Equal(t, []int64{4, 2, 8, 6}, durations)
+	assert.Equal(t, "2.00ns\t6.00ns\t8.00ns\t2.00ns\t", newBenchStats(durations).String())
+}
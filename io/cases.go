@@ -0,0 +1,70 @@
+package io
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/matematik7/codejam-go/integer"
+)
+
+func parseCases(str string) *integer.Set {
+	cases := integer.NewSet()
+	for _, part := range strings.Split(str, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		bounds := strings.SplitN(part, "-", 2)
+		from, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			log.Fatalln("Invalid case in cases:", part)
+		}
+		to := from
+		if len(bounds) == 2 {
+			to, err = strconv.Atoi(bounds[1])
+			if err != nil || to < from {
+				log.Fatalln("Invalid case range in cases:", part)
+			}
+		}
+
+		cases.Insert(integer.Range(from, to+1)...)
+	}
+	return cases
+}
+
+func (parser *Parser) selected(i int) bool {
+	return (parser.cases == nil || parser.cases.Contains(i)) && parser.inShard(i)
+}
+
+func (parser *Parser) skipTestCase(i int) {
+	parser.output.init(parser.input, i)
+	parser.input.init(parser.caseSeed(i))
+	if parser.validator != nil {
+		parser.validator(parser.input)
+		return
+	}
+
+	quiet := parser.output.quiet
+	parser.output.quiet = true
+	defer func() {
+		parser.output.quiet = quiet
+		parser.output.reset()
+	}()
+
+	parser.f(parser.input, parser.output)
+}
+
+type casesValue struct {
+	parser *Parser
+}
+
+func (cv casesValue) String() string {
+	return ""
+}
+
+func (cv casesValue) Set(str string) error {
+	cv.parser.cases = parseCases(str)
+	return nil
+}
@@ -0,0 +1,20 @@
+package io
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCases(t *testing.T) {
+	cases := parseCases("3,7, 10-12")
+	assert.Equal(t, 5, cases.Len())
+	assert.True(t, cases.ContainsAll(3, 7, 10, 11, 12))
+	assert.False(t, cases.ContainsAny(1, 2, 4, 9, 13))
+
+	parser := &Parser{}
+	assert.True(t, parser.selected(1))
+	parser.cases = cases
+	assert.True(t, parser.selected(11))
+	assert.False(t, parser.selected(1))
+}
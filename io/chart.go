@@ -0,0 +1,181 @@
+//go:build !submit
+// +build !submit
+
+package io
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gonum/plot"
+	"github.com/gonum/plot/palette"
+	"github.com/gonum/plot/plotter"
+	"github.com/gonum/plot/plotutil"
+	"github.com/gonum/plot/vg"
+)
+
+type heatGrid [][]float64
+
+func (g heatGrid) Dims() (int, int) {
+	if len(g) == 0 {
+		return 0, 0
+	}
+	return len(g[0]), len(g)
+}
+
+func (g heatGrid) Z(c, r int) float64 {
+	return g[r][c]
+}
+
+func (g heatGrid) X(c int) float64 {
+	return float64(c)
+}
+
+func (g heatGrid) Y(r int) float64 {
+	return float64(r)
+}
+
+func (parser *Parser) writePlots(output *Output, i int) {
+	if len(output.points) > 0 || len(output.series) > 0 || len(output.scatter) > 0 {
+		p := newPlot()
+		customizePlot(p, output)
+		lines := []interface{}{}
+		if len(output.points) > 0 {
+			lines = append(lines, "", output.points)
+		}
+		for _, s := range output.series {
+			lines = append(lines, s.name, s.points)
+		}
+		if err := plotutil.AddLinePoints(p, lines...); err != nil {
+			log.Fatalln("Error adding linepoints:", err)
+		}
+		if len(output.scatter) > 0 {
+			if err := plotutil.AddScatters(p, output.scatter); err != nil {
+				log.Fatalln("Error adding scatter:", err)
+			}
+		}
+		parser.savePlot(p, i, "")
+	}
+	if len(output.hist) > 0 {
+		parser.savePlot(histogramPlot(output.hist, output.bins), i, ".hist")
+	}
+	if c, r := heatGrid(output.heatmap).Dims(); c > 0 && r > 0 {
+		parser.savePlot(heatmapPlot(output.heatmap), i, ".heatmap")
+	}
+}
+
+func newPlot() *plot.Plot {
+	p, err := plot.New()
+	if err != nil {
+		log.Fatalln("Error creating plot:", err)
+	}
+	return p
+}
+
+func customizePlot(p *plot.Plot, output *Output) {
+	p.Title.Text = output.chartTitle
+	p.X.Label.Text = output.xLabel
+	p.Y.Label.Text = output.yLabel
+	if output.logX {
+		p.X.Scale = plot.LogScale{}
+		p.X.Tick.Marker = plot.LogTicks{}
+	}
+	if output.logY {
+		p.Y.Scale = plot.LogScale{}
+		p.Y.Tick.Marker = plot.LogTicks{}
+	}
+}
+
+func histogramPlot(values []float64, bins int) *plot.Plot {
+	if bins <= 0 {
+		bins = 10
+	}
+	h, err := plotter.NewHist(plotter.Values(values), bins)
+	if err != nil {
+		log.Fatalln("Error creating histogram:", err)
+	}
+	p := newPlot()
+	p.Add(h)
+	return p
+}
+
+func heatmapPlot(matrix [][]float64) *plot.Plot {
+	p := newPlot()
+	p.Add(plotter.NewHeatMap(heatGrid(matrix), palette.Heat(12, 1)))
+	return p
+}
+
+func (parser *Parser) savePlot(p *plot.Plot, i int, suffix string) {
+	parser.savePlotAs(p, parser.baseFn+strconv.Itoa(i)+suffix)
+}
+
+func (parser *Parser) savePlotAs(p *plot.Plot, fn string) {
+	width, height, format := parser.chartWidth, parser.chartHeight, parser.chartFormat
+	if width <= 0 || height <= 0 {
+		width, height = 4, 4
+	}
+	if format == "" {
+		format = "png"
+	}
+	if err := p.Save(vg.Length(width)*vg.Inch, vg.Length(height)*vg.Inch, fn+"."+format); err != nil {
+		log.Fatalln("Error saving img:", err)
+	}
+}
+
+func barPlot(title, label string, results []caseResult, value func(caseResult) float64) *plot.Plot {
+	values := make(plotter.Values, len(results))
+	names := make([]string, len(results))
+	for j, r := range results {
+		values[j] = value(r)
+		names[j] = strconv.Itoa(r.caseN)
+	}
+	bars, err := plotter.NewBarChart(values, vg.Points(8))
+	if err != nil {
+		log.Fatalln("Error creating bar chart:", err)
+	}
+	p := newPlot()
+	p.Title.Text = title
+	p.X.Label.Text = "case"
+	p.Y.Label.Text = label
+	p.Add(bars)
+	if len(results) <= 50 {
+		p.NominalX(names...)
+	}
+	return p
+}
+
+func (parser *Parser) writeTimingChart(results []caseResult) {
+	if len(results) == 0 {
+		return
+	}
+	parser.savePlotAs(barPlot("Time", "ms", results, func(r caseResult) float64 {
+		return float64(r.duration) / float64(time.Millisecond)
+	}), parser.baseFn+".timing")
+	parser.savePlotAs(barPlot("Memory", "MB", results, func(r caseResult) float64 {
+		return float64(r.memory) / (1 << 20)
+	}), parser.baseFn+".memory")
+}
+
+func (parser *Parser) writeChart(output *Output, i int) {
+	if parser.quiet {
+		output.resetCharts()
+		return
+	}
+
+	parser.writePlots(output, i)
+	if parser.pointsCSV && (len(output.points) > 0 || len(output.series) > 0 || len(output.scatter) > 0) {
+		parser.savePointsCSV(output, i)
+	}
+	if len(output.grid) > 0 {
+		parser.saveGrid(output.grid, output.palette, i)
+	}
+	if len(output.frames) > 0 {
+		parser.saveFrames(output.frames, output.palette, i)
+	}
+	if len(output.graph) > 0 {
+		parser.saveGraph(output.graph, i)
+	}
+
+	output.resetCharts()
+}
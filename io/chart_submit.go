@@ -0,0 +1,14 @@
+//go:build submit
+// +build submit
+
+package io
+
+func (parser *Parser) writePlots(output *Output, i int) {
+}
+
+func (parser *Parser) writeTimingChart(results []caseResult) {
+}
+
+func (parser *Parser) writeChart(output *Output, i int) {
+	output.resetCharts()
+}
@@ -0,0 +1,86 @@
+package io
+
+import (
+	"encoding/csv"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type chartXYs []struct{ X, Y float64 }
+
+func (xys chartXYs) Len() int {
+	return len(xys)
+}
+
+func (xys chartXYs) XY(i int) (float64, float64) {
+	return xys[i].X, xys[i].Y
+}
+
+type chartSeries struct {
+	name   string
+	points chartXYs
+}
+
+func writePointsCSV(w io.Writer, output *Output) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"series", "x", "y"})
+	write := func(series string, points chartXYs) {
+		for _, p := range points {
+			cw.Write([]string{series, strconv.FormatFloat(p.X, 'g', -1, 64), strconv.FormatFloat(p.Y, 'g', -1, 64)})
+		}
+	}
+	write("", output.points)
+	for _, s := range output.series {
+		write(s.name, s.points)
+	}
+	write("scatter", output.scatter)
+	cw.Flush()
+	return cw.Error()
+}
+
+func (parser *Parser) savePointsCSV(output *Output, i int) {
+	f, err := os.Create(parser.baseFn + strconv.Itoa(i) + ".csv")
+	if err != nil {
+		log.Fatalln("Error creating points csv:", err)
+	}
+	defer f.Close()
+
+	if err := writePointsCSV(f, output); err != nil {
+		log.Fatalln("Error writing points csv:", err)
+	}
+}
+
+func parseChartSize(str string) (float64, float64) {
+	parts := strings.SplitN(str, "x", 2)
+	if len(parts) != 2 {
+		log.Fatalln("Invalid chart size, expected WxH in inches:", str)
+	}
+	width, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil || width <= 0 {
+		log.Fatalln("Invalid chart width:", parts[0])
+	}
+	height, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil || height <= 0 {
+		log.Fatalln("Invalid chart height:", parts[1])
+	}
+	return width, height
+}
+
+type chartSizeValue struct {
+	parser *Parser
+}
+
+func (cv chartSizeValue) String() string {
+	if cv.parser == nil || cv.parser.chartWidth == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(cv.parser.chartWidth, 'g', -1, 64) + "x" + strconv.FormatFloat(cv.parser.chartHeight, 'g', -1, 64)
+}
+
+func (cv chartSizeValue) Set(str string) error {
+	cv.parser.chartWidth, cv.parser.chartHeight = parseChartSize(str)
+	return nil
+}
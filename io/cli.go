@@ -0,0 +1,235 @@
+package io
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type command struct {
+	usage string
+	flags func(parser *Parser, flags *flag.FlagSet)
+	run   func(parser *Parser, args []string)
+}
+
+var commands map[string]command
+
+func usage(flags *flag.FlagSet) func() {
+	return func() {
+		names := make([]string, 0, len(commands))
+		for name := range commands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
+		for _, name := range names {
+			fmt.Fprintf(os.Stderr, "  %s %s\n", name, commands[name].usage)
+		}
+		fmt.Fprintf(os.Stderr, "Flags of %s:\n", flags.Name())
+		flags.PrintDefaults()
+	}
+}
+
+func (parser *Parser) newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.Usage = usage(flags)
+	flags.BoolVar(&parser.quiet, "quiet", parser.quiet, "suppress debug output, timing and profiling")
+	flags.IntVar(&parser.verbosity, "v", parser.verbosity, "debug verbosity, 0 hides output.Debug, 2 shows output.Debugv and 3 shows output.Trace")
+	flags.DurationVar(&parser.period, "period", parser.period, "interval of output.Periodic prints and output.EveryPeriod callbacks (default 1s)")
+	flags.BoolVar(&parser.noProfile, "noprofile", parser.noProfile, "disable cpu profiling of long cases")
+	flags.DurationVar(&parser.profileStart, "profilestart", parser.profileStart, "start cpu profiling cases that run longer than this")
+	flags.DurationVar(&parser.profileStop, "profilestop", parser.profileStop, "cpu profiling duration, 0 to profile until the end of the case")
+	flags.BoolVar(&parser.memProfile, "memprofile", parser.memProfile, "write heap profile at the end of cases with a new peak memory, at most 10 per file, and print top allocators")
+	flags.IntVar(&parser.memThreshold, "memthreshold", parser.memThreshold, "write heap profile and print top allocators when heap in use exceeds this many MB")
+	flags.IntVar(&parser.gcPercent, "gogc", parser.gcPercent, "garbage collection target percentage while solving, -1 disables gc, 0 keeps GOGC")
+	flags.BoolVar(&parser.gcBetween, "gcbetween", parser.gcBetween, "run garbage collection before every case so timings are not polluted by previous cases")
+	flags.DurationVar(&parser.timeLimit, "timelimit", parser.timeLimit, "time limit per case, exceeding cases are marked as TLE")
+	flags.DurationVar(&parser.fileTimeLimit, "filetimelimit", parser.fileTimeLimit, "time budget of a whole input file, only reported to solutions by input.Deadline()")
+	flags.BoolVar(&parser.skipTLE, "skiptle", parser.skipTLE, "skip cases that exceed time limit and continue with the next one, cases are read ahead with io.WithValidator which is required")
+	flags.StringVar(&parser.format, "format", parser.format, "input format, t for number of cases first, single for one case per file, eof for cases until end of input")
+	flags.StringVar(&parser.delimiters, "delimiters", parser.delimiters, "characters separating tokens in addition to whitespace, e.g. \",;\"")
+	flags.BoolVar(&parser.strict, "strict", parser.strict, "fail files where a case does not read to the end of a line or input is left after the last case")
+	flags.StringVar(&parser.casePrefix, "prefix", parser.casePrefix, "case prefix of output files, %d is the case number (default \"Case #%d:\")")
+	flags.BoolVar(&parser.prefixLine, "prefixline", parser.prefixLine, "write case prefix on its own line")
+	flags.BoolVar(&parser.noPrefix, "noprefix", parser.noPrefix, "write output without case prefix")
+	flags.StringVar(&parser.tee, "tee", parser.tee, "also write answers of input files to stdout or stderr as they are produced")
+	flags.BoolVar(&parser.caseOutputs, "caseout", parser.caseOutputs, "also write the answer of every case to its own .caseN.out file")
+	flags.BoolVar(&parser.autoFlush, "autoflush", parser.autoFlush, "flush output after every case, so stdout keeps all finished cases when the solution crashes")
+	flags.BoolVar(&parser.raw, "raw", parser.raw, "write output exactly as printed, without case prefix and added newlines, empty cases are allowed")
+	flags.Var(chartSizeValue{parser}, "chartsize", "size of charts in inches, e.g. 6x4 (default 4x4)")
+	flags.StringVar(&parser.chartFormat, "chartformat", parser.chartFormat, "file format of charts, png, svg or pdf (default png)")
+	flags.BoolVar(&parser.pointsCSV, "pointscsv", parser.pointsCSV, "also write chart points of every case to csv with series, x and y columns")
+	flags.BoolVar(&parser.progress, "progress", parser.progress, "show a progress bar of finished cases with elapsed time and ETA")
+	flags.BoolVar(&parser.timingChart, "timingchart", parser.timingChart, "write bar charts of time and memory of every case to .timing and .memory charts after each file")
+	flags.IntVar(&parser.precision, "precision", parser.precision, "digits after the decimal point of printed float64 values, -1 for shortest representation")
+	flags.Int64Var(&parser.seed, "seed", parser.seed, "run-wide random seed, case i uses seed+i for input.Rand()")
+	flags.BoolVar(&parser.anyLineOrder, "anyorder", parser.anyLineOrder, "lines of each case can be in any order when comparing with correct output")
+	flags.Var(normalizersValue{parser}, "normalize", "normalize output and correct output before comparing, comma separated trim, lower, spaces, sort")
+	flags.Var(correctExtsValue{parser}, "correct", "comma separated extensions of correct output files, first existing is used (default .correct,.ans,.expected,.out.ok)")
+	flags.StringVar(&parser.referenceCmd, "reference", parser.referenceCmd, "reference solution command used instead of correct output and brute force, e.g. \"python brute.py\"")
+	flags.Var(toleranceValue{parser}, "tolerance", "absolute and relative tolerance for comparing numbers with correct output, e.g. 1e-6")
+	if cmd, ok := commands[name]; ok && cmd.flags != nil {
+		cmd.flags(parser, flags)
+	}
+	return flags
+}
+
+func (parser *Parser) parseFlagSet(flags *flag.FlagSet, args []string) []string {
+	parser.applyConfig(flags)
+	if err := flags.Parse(args); err != nil {
+		log.Fatalln("Error parsing flags:", err)
+	}
+	return flags.Args()
+}
+
+func (parser *Parser) runFlags(flags *flag.FlagSet) {
+	flags.BoolVar(&parser.interactive, "interactive", parser.interactive, "interactive problem, read from stdin and write to stdout")
+	flags.IntVar(&parser.parallelism, "parallel", parser.parallelism, "number of input files to solve concurrently")
+	flags.BoolVar(&parser.failedOnly, "failed", parser.failedOnly, "run only cases that failed in the previous run and merge them into existing output")
+	flags.Var(casesValue{parser}, "cases", "run only selected cases, e.g. 3,7,10-15, other cases are read by io.WithValidator, without one they are still solved without output to consume their input, so earlier cases still take their time")
+	flags.Var(shardValue{parser}, "shard", "run only cases of shard i of n (0 <= i < n), case c is in shard (c-1)%n, other cases are read by io.WithValidator, output is written to .shardiofn.out")
+	flags.BoolVar(&parser.showSummary, "summary", parser.showSummary, "print summary table of all cases at the end")
+	flags.IntVar(&parser.slowest, "slowest", parser.slowest, "print the n slowest cases with the number of input tokens they read after the summary, 0 to disable")
+	flags.StringVar(&parser.reportFn, "report", parser.reportFn, "write per case report to file, csv if it ends with .csv, json otherwise")
+	flags.StringVar(&parser.notify, "notify", parser.notify, "notify when the run finishes and on the first failed case, desktop or a slack or telegram compatible webhook url receiving {\"text\": message}")
+	flags.StringVar(&parser.httpAddr, "http", parser.httpAddr, "serve a live dashboard with case status, timings, debug output and charts of the run on address, e.g. :8080")
+	flags.StringVar(&parser.archiveDir, "archive", parser.archiveDir, "copy .out files and a timing report.json of the run into a timestamped subdirectory of dir, e.g. runs")
+	flags.BoolVar(&parser.writeSha, "writesha", parser.writeSha, "write per case sha256 hashes of output to .sha file")
+	flags.BoolVar(&parser.determinism, "determinism", parser.determinism, "solve every input file once more and fail on cases with different output than the run")
+	flags.IntVar(&parser.benchRuns, "bench", parser.benchRuns, "solve input files n times without output and report per case min, median, max and stddev time")
+	flags.BoolVar(&parser.validateOnly, "validate", parser.validateOnly, "only check input files or stdin against the validator constraints")
+	flags.BoolVar(&parser.sandbox, "sandbox", parser.sandbox, "solve every input file in a child process with cpu and memory limits, crashes are reported as TLE, MLE or RE")
+	flags.DurationVar(&parser.cpuLimit, "cpulimit", parser.cpuLimit, "cpu time limit of sandbox child process, rounded up to seconds")
+	flags.IntVar(&parser.memLimit, "memlimit", parser.memLimit, "address space limit of sandbox child process in MB")
+	flags.BoolVar(&parser.sandboxChild, "sandboxchild", parser.sandboxChild, "internal, run as sandbox child process")
+	flags.StringVar(&parser.judgeCmd, "judge", parser.judgeCmd, "interactive judge command to run the solution against, e.g. \"python testing_tool.py 0\"")
+	flags.StringVar(&parser.recordFn, "record", parser.recordFn, "write interactive transcript of judge and solution lines to file, judge mode writes judge.transcript by default")
+	flags.StringVar(&parser.replayFn, "replay", parser.replayFn, "replay judge responses from a recorded transcript and check that the solution writes the same lines")
+}
+
+func (parser *Parser) runCommand(args []string) {
+	flags := parser.newFlagSet("run")
+	inputFns := parser.parseFlagSet(flags, args)
+	if len(inputFns) == 0 {
+		inputFns = parser.inputFns
+	}
+	inputFns = parser.downloadInputs(expandGlobs(inputFns))
+	if parser.failedOnly || parser.writeSha || parser.determinism {
+		parser.outputPrefix()
+	}
+	if parser.shardCount > 0 && parser.validator == nil {
+		log.Fatalln("Sharding needs io.WithValidator to skip cases of other shards without solving them")
+	}
+	if parser.skipTLE && parser.validator == nil {
+		log.Fatalln("Skipping TLE cases needs io.WithValidator to read the input of the skipped case")
+	}
+	if parser.cases != nil && parser.validator == nil && !parser.quiet {
+		log.Println("No io.WithValidator, cases that are not selected are still solved without output to read their input")
+	}
+
+	if parser.sandboxChild {
+		parser.runSandboxChild()
+		return
+	}
+	if !parser.quiet && len(inputFns) > 0 {
+		log.Println("Seed:", parser.seed)
+	}
+	parser.startDashboard()
+
+	if parser.benchRuns > 0 {
+		parser.benchFiles(inputFns, parser.benchRuns)
+		return
+	}
+	if parser.validateOnly {
+		parser.validateFiles(inputFns)
+		return
+	}
+	if parser.replayFn != "" && parser.judgeCmd != "" {
+		log.Fatalln("-replay and -judge cannot be used together")
+	}
+	if parser.replayFn != "" {
+		parser.ParseReplay(parser.replayFn)
+		return
+	}
+	if parser.judgeCmd != "" {
+		parser.ParseJudge(parser.judgeCmd)
+		return
+	}
+	if parser.interactive {
+		parser.ParseInteractive()
+		return
+	}
+	if len(inputFns) == 0 {
+		parser.ParseStdin()
+		return
+	}
+	defer parser.handleInterrupt()()
+	parser.notifier = newNotifier(parser.notify)
+	parser.ParseFiles(inputFns)
+	parser.printSummary()
+	parser.writeReport()
+	if parser.isInterrupted() {
+		return
+	}
+	parser.notifyFinished()
+	if dir := parser.archiveRun(inputFns, time.Now()); dir != "" && !parser.quiet {
+		log.Println("Archived run to", dir)
+	}
+}
+
+func (parser *Parser) judgeCommand(args []string) {
+	flags := parser.newFlagSet("judge")
+	judgeArgs := parser.parseFlagSet(flags, args)
+	if len(judgeArgs) > 0 {
+		parser.judgeCmd = joinCommand(judgeArgs)
+	}
+	if parser.judgeCmd == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	parser.ParseJudge(parser.judgeCmd)
+}
+
+type toleranceValue struct {
+	parser *Parser
+}
+
+func (tv toleranceValue) String() string {
+	if tv.parser == nil {
+		return ""
+	}
+	return strconv.FormatFloat(tv.parser.absTolerance, 'g', -1, 64)
+}
+
+func (tv toleranceValue) Set(str string) error {
+	tolerance, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return err
+	}
+	tv.parser.absTolerance = tolerance
+	tv.parser.relTolerance = tolerance
+	return nil
+}
+
+type correctExtsValue struct {
+	parser *Parser
+}
+
+func (cv correctExtsValue) String() string {
+	if cv.parser == nil {
+		return ""
+	}
+	return strings.Join(cv.parser.correctExts, ",")
+}
+
+func (cv correctExtsValue) Set(str string) error {
+	cv.parser.correctExts = strings.Split(str, ",")
+	return nil
+}
@@ -0,0 +1,29 @@
+package io
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitCommand(t *testing.T) {
+	args, err := splitCommand(`python 'my brute.py' "a \"b\"" c\ d ''`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"python", "my brute.py", `a "b"`, "c d", ""}, args)
+
+	args, err = splitCommand("  ")
+	assert.NoError(t, err)
+	assert.Empty(t, args)
+
+	_, err = splitCommand(`python "brute.py`)
+	assert.Error(t, err)
+}
+
+func TestJoinCommand(t *testing.T) {
+	args := []string{"python", "my tool.py", "it's", "", `a"b`}
+	command := joinCommand(args)
+	assert.Equal(t, `python 'my tool.py' 'it'\''s' '' 'a"b'`, command)
+	split, err := splitCommand(command)
+	assert.NoError(t, err)
+	assert.Equal(t, args, split)
+}
@@ -0,0 +1,49 @@
+//go:build !submit
+// +build !submit
+
+package io
+
+func init() {
+	commands = map[string]command{
+		"run": {
+			usage: "[flags] [input files], solve input files, stdin if none",
+			flags: (*Parser).runFlags,
+			run:   (*Parser).runCommand,
+		},
+		"judge": {
+			usage: "[flags] judge command, solve interactive problem against a local judge",
+			run:   (*Parser).judgeCommand,
+		},
+		"gen": {
+			usage: "[flags] [cases], write generated input to stdout",
+			run:   (*Parser).genCommand,
+		},
+		"stress": {
+			usage: "[flags], compare solution with brute force on generated inputs",
+			flags: (*Parser).stressFlags,
+			run:   (*Parser).stressCommand,
+		},
+		"merge": {
+			usage: "[-o file] output files, merge partial outputs of shards",
+			flags: (*Parser).mergeFlags,
+			run:   (*Parser).mergeCommand,
+		},
+		"bench": {
+			usage: "[flags] input files, solve input files repeatedly and report timing",
+			flags: (*Parser).benchFlags,
+			run:   (*Parser).benchCommand,
+		},
+	}
+}
+
+func (parser *Parser) Run() {
+	name := "run"
+	args := parser.args
+	if len(args) > 0 {
+		if _, ok := commands[args[0]]; ok {
+			name = args[0]
+			args = args[1:]
+		}
+	}
+	commands[name].run(parser, args)
+}
@@ -0,0 +1,12 @@
+//go:build submit
+// +build submit
+
+package io
+
+func (parser *Parser) Run() {
+	if parser.interactive {
+		parser.ParseInteractive()
+		return
+	}
+	parser.ParseStdin()
+}
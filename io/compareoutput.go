@@ -5,7 +5,10 @@ import (
 	"io"
 	"io/ioutil"
 	"log"
+	"math"
+	"sort"
 	"strconv"
+	"strings"
 )
 
 type CompareOutput struct {
@@ -13,6 +16,10 @@ type CompareOutput struct {
 }
 
 func NewCompareOutput(correctF io.Reader) *CompareOutput {
+	return newCompareOutputPrefix(correctF, "Case #", ":")
+}
+
+func newCompareOutputPrefix(correctF io.Reader, before, after string) *CompareOutput {
 	co := &CompareOutput{
 		outputs: make(map[int][]byte),
 	}
@@ -22,28 +29,58 @@ func NewCompareOutput(correctF io.Reader) *CompareOutput {
 		log.Fatalln("Error opening correct file:", err)
 	}
 
-	casesData := bytes.Split(correctData, []byte("Case #"))
+	casesData := bytes.Split(correctData, []byte(before))
 	for _, caseData := range casesData {
 		if len(caseData) == 0 {
 			continue
 		}
 
-		colonIndex := bytes.Index(caseData, []byte(":"))
-		if colonIndex == -1 {
-			log.Fatalln("No colon in correct case:", caseData)
+		end := 0
+		if after == "" {
+			for end < len(caseData) && caseData[end] >= '0' && caseData[end] <= '9' {
+				end++
+			}
+		} else {
+			end = bytes.Index(caseData, []byte(after))
+			if end == -1 {
+				log.Fatalf("No %q in correct case: %s\n", after, caseData)
+			}
 		}
 
-		caseNumber, err := strconv.Atoi(string(caseData[:colonIndex]))
+		caseNumber, err := strconv.Atoi(string(caseData[:end]))
 		if err != nil {
-			log.Fatalln("Invalid case number in correct file:", string(caseData[:colonIndex]))
+			log.Fatalln("Invalid case number in correct file:", string(caseData[:end]))
 		}
 
-		co.outputs[caseNumber] = caseData[colonIndex+1:]
+		co.outputs[caseNumber] = caseData[end+len(after):]
 	}
 
 	return co
 }
 
+func (parser *Parser) splitsCases() bool {
+	return !parser.noPrefix && !parser.raw
+}
+
+func (parser *Parser) outputPrefix() (string, string) {
+	if parser.noPrefix || parser.raw {
+		log.Fatalln("Outputs without case prefix cannot be split into cases, -noprefix and -raw do not work with -reference, -failed, merge, -writesha and -determinism")
+	}
+	if parser.casePrefix == "" {
+		return "Case #", ":"
+	}
+	parts := strings.SplitN(parser.casePrefix, "%d", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		log.Fatalf("Case prefix %q needs text before %%d to split outputs into cases\n", parser.casePrefix)
+	}
+	return parts[0], parts[1]
+}
+
+func (parser *Parser) newCompareOutput(r io.Reader) *CompareOutput {
+	before, after := parser.outputPrefix()
+	return newCompareOutputPrefix(r, before, after)
+}
+
 func (co *CompareOutput) HasOutput(i int) bool {
 	_, ok := co.outputs[i]
 	return ok
@@ -52,3 +89,52 @@ func (co *CompareOutput) HasOutput(i int) bool {
 func (co *CompareOutput) GetOutput(i int) []byte {
 	return co.outputs[i]
 }
+
+func tokensEqual(produced, correct string, absTolerance, relTolerance float64) bool {
+	producedTokens := strings.Fields(produced)
+	correctTokens := strings.Fields(correct)
+	if len(producedTokens) != len(correctTokens) {
+		return false
+	}
+
+	for i := range correctTokens {
+		if !tokenSame(producedTokens[i], correctTokens[i], absTolerance, relTolerance, false) {
+			return false
+		}
+	}
+	return true
+}
+
+func tokenSame(produced, correct string, absTolerance, relTolerance float64, ignoreCase bool) bool {
+	if produced == correct || ignoreCase && strings.EqualFold(produced, correct) {
+		return true
+	}
+	if absTolerance == 0 && relTolerance == 0 || !isFloatToken(produced) && !isFloatToken(correct) {
+		return false
+	}
+
+	p, err := strconv.ParseFloat(produced, 64)
+	if err != nil {
+		return false
+	}
+	c, err := strconv.ParseFloat(correct, 64)
+	if err != nil {
+		return false
+	}
+
+	diff := math.Abs(p - c)
+	return diff <= absTolerance || diff <= relTolerance*math.Abs(c)
+}
+
+func isFloatToken(token string) bool {
+	return strings.ContainsAny(token, ".eE")
+}
+
+func sortLines(data string) string {
+	lines := strings.Split(data, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
@@ -22,3 +22,32 @@ test2`
 	assert.Equal(t, []byte(" test\n"), co.GetOutput(3))
 	assert.Equal(t, []byte(" test1\ntest2"), co.GetOutput(123))
 }
+
+func TestCompareOutputPrefix(t *testing.T) {
+	parser := newParser(double, WithCasePrefix("#%d)", false))
+	co := parser.newCompareOutput(strings.NewReader("#1) 2\n#2) 4\n"))
+	assert.Equal(t, []byte(" 2\n"), co.GetOutput(1))
+	assert.Equal(t, []byte(" 4\n"), co.GetOutput(2))
+
+	parser = newParser(double, WithCasePrefix("Answer %d", true))
+	co = parser.newCompareOutput(strings.NewReader("Answer 1\n2\nAnswer 12\n4\n"))
+	assert.Equal(t, []byte("\n2\n"), co.GetOutput(1))
+	assert.Equal(t, []byte("\n4\n"), co.GetOutput(12))
+}
+
+func TestTokensEqual(t *testing.T) {
+	assert.True(t, tokensEqual("1.0000001 abc", "1 abc", 1e-6, 1e-6))
+	assert.True(t, tokensEqual("1000000.5", "1000000", 1e-6, 1e-6))
+	assert.False(t, tokensEqual("1.01", "1", 1e-6, 1e-6))
+	assert.False(t, tokensEqual("1 abd", "1 abc", 1e-6, 1e-6))
+	assert.False(t, tokensEqual("1", "1 2", 1e-6, 1e-6))
+	assert.False(t, tokensEqual("1.0000001", "1", 0, 0))
+	assert.False(t, tokensEqual("1000000000000000001", "1000000000000000000", 1e-6, 1e-6))
+	assert.False(t, tokensEqual("1000001", "1000000", 1e-6, 1e-6))
+	assert.True(t, tokensEqual("1e18", "1000000000000000000", 1e-6, 1e-6))
+}
+
+func TestSortLines(t *testing.T) {
+	assert.Equal(t, "1\na b\nc", sortLines("c\n a b\n1"))
+	assert.Equal(t, sortLines("2\nx\ny"), sortLines("y\n2\nx "))
+}
@@ -0,0 +1,116 @@
+package io
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+type gzipFile struct {
+	*gzip.Reader
+	f *os.File
+}
+
+func (gf gzipFile) Close() error {
+	gf.Reader.Close()
+	return gf.f.Close()
+}
+
+type zstdFile struct {
+	io.ReadCloser
+	cmd    *exec.Cmd
+	stderr *bytes.Buffer
+	waited bool
+}
+
+func (zf *zstdFile) wait() error {
+	if zf.waited {
+		return nil
+	}
+	zf.waited = true
+	if err := zf.cmd.Wait(); err != nil {
+		return fmt.Errorf("zstd: %v: %s", err, bytes.TrimSpace(zf.stderr.Bytes()))
+	}
+	return nil
+}
+
+func (zf *zstdFile) Read(p []byte) (int, error) {
+	n, err := zf.ReadCloser.Read(p)
+	if err == io.EOF {
+		if werr := zf.wait(); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+func (zf *zstdFile) Close() error {
+	zf.ReadCloser.Close()
+	if !zf.waited {
+		zf.cmd.Process.Kill()
+	}
+	zf.wait()
+	return nil
+}
+
+func openZstd(fn string) (io.ReadCloser, error) {
+	if _, err := exec.LookPath("zstd"); err != nil {
+		return nil, fmt.Errorf("reading %s needs the zstd command, install zstd or decompress the file", fn)
+	}
+	if _, err := os.Stat(fn); err != nil {
+		return nil, err
+	}
+
+	zf := &zstdFile{
+		cmd:    exec.Command("zstd", "-dc", fn),
+		stderr: &bytes.Buffer{},
+	}
+	zf.cmd.Stderr = zf.stderr
+	stdout, err := zf.cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	zf.ReadCloser = stdout
+	if err := zf.cmd.Start(); err != nil {
+		return nil, err
+	}
+	return zf, nil
+}
+
+func trimCompression(fn string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(fn, ".gz"), ".zst")
+}
+
+func openInput(fn string) (io.ReadCloser, error) {
+	if strings.HasSuffix(fn, ".zst") {
+		return openZstd(fn)
+	}
+
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(fn, ".gz") {
+		return f, nil
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return gzipFile{gz, f}, nil
+}
+
+func readInput(fn string) ([]byte, error) {
+	r, err := openInput(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
@@ -0,0 +1,56 @@
+package io
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGzipInput(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	inputFn := filepath.Join(dir, "A-large.in.gz")
+	f, err := os.Create(inputFn)
+	assert.NoError(t, err)
+	gz := gzip.NewWriter(f)
+	gz.Write([]byte("2\n1\n2\n"))
+	gz.Close()
+	f.Close()
+
+	parser := newParser(double, WithArgs(inputFn), WithNoProfile())
+	parser.Run()
+
+	out, err := ioutil.ReadFile(filepath.Join(dir, "A-large.out"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Case #1: 2\nCase #2: 4\n", string(out))
+}
+
+func TestZstdInput(t *testing.T) {
+	if _, err := exec.LookPath("zstd"); err != nil {
+		t.Skip("zstd not installed")
+	}
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	inputFn := filepath.Join(dir, "A-large.in")
+	writeTestFile(t, inputFn, "2\n1\n2\n")
+	assert.NoError(t, exec.Command("zstd", "-q", "--rm", inputFn).Run())
+
+	data, err := readInput(inputFn + ".zst")
+	assert.NoError(t, err)
+	assert.Equal(t, "2\n1\n2\n", string(data))
+
+	corruptFn := filepath.Join(dir, "B.in.zst")
+	writeTestFile(t, corruptFn, "not zstd")
+	_, err = readInput(corruptFn)
+	assert.Error(t, err)
+
+	_, err = openInput(filepath.Join(dir, "missing.in.zst"))
+	assert.True(t, os.IsNotExist(err))
+}
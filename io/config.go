@@ -0,0 +1,101 @@
+package io
+
+import (
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var defaultConfigFns = []string{".codejam.yaml", ".codejam.yml", "codejam.toml"}
+
+type configEntry struct {
+	key   string
+	value string
+}
+
+func parseConfig(data string) []configEntry {
+	entries := []configEntry{}
+	for n, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' || line[0] == '[' || line == "---" {
+			continue
+		}
+		sep := strings.IndexAny(line, ":=")
+		if sep < 0 {
+			log.Fatalf("Invalid config line %d, expected key: value or key = value: %s\n", n+1, line)
+		}
+		key := strings.TrimSpace(line[:sep])
+		value := strings.TrimSpace(line[sep+1:])
+		if len(value) > 0 && (value[0] == '"' || value[0] == '\'') {
+			end := strings.LastIndexByte(value, value[0])
+			if end == 0 {
+				log.Fatalf("Invalid config line %d, unterminated string: %s\n", n+1, line)
+			}
+			if value[0] == '"' {
+				unquoted, err := strconv.Unquote(value[:end+1])
+				if err != nil {
+					log.Fatalf("Invalid config line %d, %v: %s\n", n+1, err, line)
+				}
+				value = unquoted
+			} else {
+				value = value[1:end]
+			}
+		} else if comment := strings.Index(value, " #"); comment >= 0 {
+			value = strings.TrimSpace(value[:comment])
+		}
+		key = strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+		entries = append(entries, configEntry{key, value})
+	}
+	return entries
+}
+
+func (parser *Parser) loadConfig() []configEntry {
+	fns := defaultConfigFns
+	if parser.configFn != "" {
+		fns = []string{parser.configFn}
+	}
+	for _, fn := range fns {
+		data, err := ioutil.ReadFile(fn)
+		if os.IsNotExist(err) && parser.configFn == "" {
+			continue
+		}
+		if err != nil {
+			log.Fatalln("Error reading config file:", err)
+		}
+		return parseConfig(string(data))
+	}
+	return nil
+}
+
+func (parser *Parser) configKeys() map[string]bool {
+	keys := map[string]bool{}
+	for name := range commands {
+		p := *parser
+		p.newFlagSet(name).VisitAll(func(f *flag.Flag) {
+			keys[f.Name] = true
+		})
+	}
+	return keys
+}
+
+func (parser *Parser) applyConfig(flags *flag.FlagSet) {
+	entries := parser.loadConfig()
+	if len(entries) == 0 {
+		return
+	}
+	keys := parser.configKeys()
+	for _, entry := range entries {
+		if !keys[entry.key] {
+			log.Fatalln("Unknown flag in config file:", entry.key)
+		}
+		if flags.Lookup(entry.key) == nil {
+			continue
+		}
+		if err := flags.Set(entry.key, entry.value); err != nil {
+			log.Fatalf("Invalid value %q of %s in config file: %v\n", entry.value, entry.key, err)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package io
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfig(t *testing.T) {
+	entries := parseConfig(`# problem A
+[run]
+time_limit: 20s
+prefix = "Case #%d:"
+correct: '.ans'
+parallel: 4 # cores
+`)
+	assert.Equal(t, []configEntry{
+		{"timelimit", "20s"},
+		{"prefix", "Case #%d:"},
+		{"correct", ".ans"},
+		{"parallel", "4"},
+	}, entries)
+}
+
+func TestConfigFile(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	configFn := filepath.Join(dir, "codejam.toml")
+	writeTestFile(t, configFn, "timelimit = 3s\nparallel = 2\n")
+	inputFn := filepath.Join(dir, "A.in")
+	writeTestFile(t, inputFn, "1\n1\n")
+
+	parser := newParser(double, WithArgs("-parallel", "1", inputFn), WithNoProfile(), WithConfigFile(configFn))
+	parser.Run()
+	assert.Equal(t, 3*time.Second, parser.timeLimit)
+	assert.Equal(t, 1, parser.parallelism)
+}
+
+func TestConfigOtherCommandKeys(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	configFn := filepath.Join(dir, "codejam.toml")
+	writeTestFile(t, configFn, "iterations = 5\nshrink = false\nparallel = 2\n")
+
+	parser := newParser(double, WithConfigFile(configFn))
+	parser.parseFlagSet(parser.newFlagSet("run"), nil)
+	assert.Equal(t, 2, parser.parallelism)
+	assert.Equal(t, 0, parser.stressIterations)
+
+	parser.parseFlagSet(parser.newFlagSet("stress"), nil)
+	assert.Equal(t, 5, parser.stressIterations)
+	assert.False(t, parser.stressShrink)
+	assert.True(t, parser.configKeys()["o"])
+}
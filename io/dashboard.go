@@ -0,0 +1,251 @@
+package io
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const dashboardTailLines = 200
+
+type dashboardFile struct {
+	inputFn     string
+	baseFn      string
+	chartFormat string
+	running     int
+	start       time.Time
+	results     []caseResult
+	done        bool
+}
+
+type dashboard struct {
+	sync.Mutex
+	files []*dashboardFile
+	tail  []string
+	line  []byte
+}
+
+func (d *dashboard) file(inputFn string) *dashboardFile {
+	for _, f := range d.files {
+		if f.inputFn == inputFn {
+			return f
+		}
+	}
+	return nil
+}
+
+func (d *dashboard) startCase(parser *Parser, i int) {
+	if d == nil {
+		return
+	}
+	d.Lock()
+	defer d.Unlock()
+	f := d.file(parser.inputFn)
+	if f == nil {
+		f = &dashboardFile{inputFn: parser.inputFn, baseFn: parser.baseFn, chartFormat: parser.chartFormat}
+		d.files = append(d.files, f)
+	}
+	f.running = i
+	f.start = time.Now()
+}
+
+func (d *dashboard) finishCase(inputFn string, result caseResult) {
+	if d == nil {
+		return
+	}
+	d.Lock()
+	defer d.Unlock()
+	if f := d.file(inputFn); f != nil {
+		f.running = 0
+		f.results = append(f.results, result)
+	}
+}
+
+func (d *dashboard) finishFile(inputFn string) {
+	if d == nil {
+		return
+	}
+	d.Lock()
+	defer d.Unlock()
+	if f := d.file(inputFn); f != nil {
+		f.running = 0
+		f.done = true
+	}
+}
+
+func (d *dashboard) Write(p []byte) (int, error) {
+	d.Lock()
+	defer d.Unlock()
+	d.line = append(d.line, p...)
+	for {
+		end := bytes.IndexByte(d.line, '\n')
+		if end < 0 {
+			break
+		}
+		d.tail = append(d.tail, string(d.line[:end]))
+		d.line = d.line[end+1:]
+	}
+	if len(d.tail) > dashboardTailLines {
+		d.tail = d.tail[len(d.tail)-dashboardTailLines:]
+	}
+	return len(p), nil
+}
+
+func chartFns(baseFn, format string) []string {
+	if format == "" {
+		format = "png"
+	}
+	patterns := []string{"[0-9]*." + format, "[0-9]*.gif"}
+	if format != "png" {
+		patterns = append(patterns, "[0-9]*.grid.png")
+	}
+	fns := []string{}
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(baseFn + pattern)
+		fns = append(fns, matches...)
+	}
+	sort.Strings(fns)
+	for _, suffix := range []string{".timing.", ".memory."} {
+		if _, err := os.Stat(baseFn + suffix + format); err == nil {
+			fns = append(fns, baseFn+suffix+format)
+		}
+	}
+	return fns
+}
+
+type dashboardCase struct {
+	Case     int
+	Verdict  string
+	Time     string
+	Memory   string
+	Finished bool
+}
+
+type dashboardFileView struct {
+	File   string
+	Status string
+	Cases  []dashboardCase
+	Charts []string
+}
+
+type dashboardView struct {
+	Files []dashboardFileView
+	Tail  string
+}
+
+func (d *dashboard) view() dashboardView {
+	d.Lock()
+	defer d.Unlock()
+
+	view := dashboardView{Tail: strings.Join(d.tail, "\n")}
+	for _, f := range d.files {
+		fv := dashboardFileView{File: f.inputFn, Status: "done", Charts: chartFns(f.baseFn, f.chartFormat)}
+		if !f.done {
+			fv.Status = "running"
+		}
+		for _, r := range f.results {
+			fv.Cases = append(fv.Cases, dashboardCase{
+				Case:     r.caseN,
+				Verdict:  r.verdict.String(),
+				Time:     formatDuration(r.duration.Nanoseconds()),
+				Memory:   formatBytes(r.memory),
+				Finished: true,
+			})
+		}
+		if f.running > 0 {
+			fv.Cases = append(fv.Cases, dashboardCase{
+				Case: f.running,
+				Time: formatDuration(time.Since(f.start).Nanoseconds()),
+			})
+		}
+		view.Files = append(view.Files, fv)
+	}
+	return view
+}
+
+func (d *dashboard) isChart(fn string) bool {
+	d.Lock()
+	defer d.Unlock()
+	for _, f := range d.files {
+		for _, chart := range chartFns(f.baseFn, f.chartFormat) {
+			if chart == fn {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="2">
+<title>codejam run</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+td, th { border: 1px solid #ccc; padding: 2px 8px; text-align: right; }
+.running { background: #ffd; }
+img { width: 300px; }
+pre { background: #eee; padding: 8px; }
+</style>
+</head>
+<body>
+{{range .Files}}
+<h2>{{.File}} ({{.Status}})</h2>
+<table>
+<tr><th>Case</th><th>Verdict</th><th>Time</th><th>Peak memory</th></tr>
+{{range .Cases}}{{if .Finished}}<tr><td>{{.Case}}</td><td>{{.Verdict}}</td><td>{{.Time}}</td><td>{{.Memory}}</td></tr>
+{{else}}<tr class="running"><td>{{.Case}}</td><td>running</td><td>{{.Time}}</td><td></td></tr>
+{{end}}{{end}}</table>
+{{range .Charts}}<a href="chart?fn={{.}}"><img src="chart?fn={{.}}" title="{{.}}"></a>
+{{end}}{{end}}
+<h2>Debug</h2>
+<pre>{{.Tail}}</pre>
+</body>
+</html>
+`))
+
+func (d *dashboard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := dashboardTemplate.Execute(w, d.view()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	case "/chart":
+		fn := r.URL.Query().Get("fn")
+		if !d.isChart(fn) {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeFile(w, r, fn)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (parser *Parser) startDashboard() {
+	if parser.httpAddr == "" {
+		return
+	}
+	parser.dashboard = &dashboard{}
+	log.SetOutput(io.MultiWriter(os.Stderr, parser.dashboard))
+	go func() {
+		log.Fatalln("Error serving dashboard:", http.ListenAndServe(parser.httpAddr, parser.dashboard))
+	}()
+	host := parser.httpAddr
+	if strings.HasPrefix(host, ":") {
+		host = "localhost" + host
+	}
+	log.Println("Dashboard:", "http://"+host)
+}
@@ -0,0 +1,73 @@
+package io
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDashboard(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	d := &dashboard{}
+	parser := &Parser{inputFn: filepath.Join(dir, "A.in"), baseFn: filepath.Join(dir, "A")}
+	d.startCase(parser, 1)
+	d.finishCase(parser.inputFn, caseResult{caseN: 1, verdict: OK, duration: time.Millisecond})
+	d.startCase(parser, 2)
+	fmt.Fprint(d, "first\nsec")
+	fmt.Fprint(d, "ond\nthird")
+	writeTestFile(t, filepath.Join(dir, "A1.png"), "png")
+
+	server := httptest.NewServer(d)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	assert.NoError(t, err)
+	page, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Contains(t, string(page), "(running)")
+	assert.Contains(t, string(page), "<td>OK</td>")
+	assert.Contains(t, string(page), "<td>2</td><td>running</td>")
+	assert.Contains(t, string(page), "first\nsecond</pre>")
+	assert.Contains(t, string(page), "A1.png")
+
+	resp, err = http.Get(server.URL + "/chart?fn=" + filepath.Join(dir, "A1.png"))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = http.Get(server.URL + "/chart?fn=" + filepath.Join(dir, "A.in"))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp.Body.Close()
+
+	d.finishFile(parser.inputFn)
+	assert.Equal(t, "done", d.view().Files[0].Status)
+}
+
+func TestDashboardChartFormat(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	baseFn := filepath.Join(dir, "A")
+	for _, fn := range []string{"A1.png", "A1.svg", "A1.grid.png", "A2.gif", "A.timing.svg", "A.memory.png"} {
+		writeTestFile(t, filepath.Join(dir, fn), "chart")
+	}
+	assert.Equal(t, []string{baseFn + "1.grid.png", baseFn + "1.svg", baseFn + "2.gif", baseFn + ".timing.svg"}, chartFns(baseFn, "svg"))
+	assert.Equal(t, []string{baseFn + "1.grid.png", baseFn + "1.png", baseFn + "2.gif", baseFn + ".memory.png"}, chartFns(baseFn, ""))
+}
+
+func TestDashboardNil(t *testing.T) {
+	var d *dashboard
+	d.startCase(&Parser{}, 1)
+	d.finishCase("", caseResult{})
+	d.finishFile("")
+}
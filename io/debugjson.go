@@ -0,0 +1,64 @@
+package io
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"runtime"
+)
+
+type debugJSONFile struct {
+	fn      string
+	f       *os.File
+	encoder *json.Encoder
+}
+
+func newDebugJSONFile(fn string) *debugJSONFile {
+	if err := os.Remove(fn); err != nil && !os.IsNotExist(err) {
+		log.Fatalln("Error removing debug json file:", err)
+	}
+	return &debugJSONFile{fn: fn}
+}
+
+func (df *debugJSONFile) write(values map[string]interface{}) {
+	if df.f == nil {
+		f, err := os.Create(df.fn)
+		if err != nil {
+			log.Fatalln("Error creating debug json file:", err)
+		}
+		df.f = f
+		df.encoder = json.NewEncoder(f)
+	}
+	if err := df.encoder.Encode(values); err != nil {
+		log.Fatalln("Error writing debug json:", err)
+	}
+}
+
+func (df *debugJSONFile) close() {
+	if df.f != nil {
+		df.f.Close()
+		df.f = nil
+	}
+}
+
+func (o *Output) DebugJSON(key string, value interface{}) {
+	if o.isAbandoned() {
+		runtime.Goexit()
+	}
+	if o.debugJSON == nil {
+		return
+	}
+	if o.debugValues == nil {
+		o.debugValues = map[string]interface{}{}
+	}
+	o.debugValues[key] = value
+}
+
+func (o *Output) flushDebugJSON() {
+	if o.debugJSON == nil || len(o.debugValues) == 0 {
+		return
+	}
+	o.debugValues["case"] = o.caseN
+	o.debugJSON.write(o.debugValues)
+	o.debugValues = nil
+}
@@ -0,0 +1,49 @@
+package io
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugJSON(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	inputFn := filepath.Join(dir, "A.in")
+	writeTestFile(t, inputFn, "2\n1\n2\n")
+
+	f := func(input *Input, output *Output) {
+		n := input.Int()
+		if n == 2 {
+			output.DebugJSON("score", 1.5)
+			output.DebugJSON("branches", n*10)
+		}
+		output.Print(n)
+	}
+	parser := newParser(f, WithArgs(inputFn), WithNoProfile())
+	parser.Run()
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "A.debug.jsonl"))
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"branches\":20,\"case\":2,\"score\":1.5}\n", string(data))
+}
+
+func TestDebugJSONStale(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	inputFn := filepath.Join(dir, "A.in")
+	writeTestFile(t, inputFn, "1\n1\n")
+	writeTestFile(t, filepath.Join(dir, "A.debug.jsonl"), "{\"case\":1}\n")
+
+	parser := newParser(double, WithArgs(inputFn), WithNoProfile())
+	parser.quiet = true
+	parser.Run()
+
+	_, err := os.Stat(filepath.Join(dir, "A.debug.jsonl"))
+	assert.True(t, os.IsNotExist(err))
+}
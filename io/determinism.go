@@ -0,0 +1,43 @@
+package io
+
+import (
+	"bytes"
+	"log"
+)
+
+func (parser *Parser) nondeterministicCases(first *CompareOutput, caseNs []int, input []byte) []int {
+	output, _ := parser.solve(parser.f, input)
+	second := NewCompareOutput(bytes.NewReader(output))
+
+	cases := []int{}
+	for _, i := range caseNs {
+		firstOutput, secondOutput := bytes.TrimSpace(first.GetOutput(i)), bytes.TrimSpace(second.GetOutput(i))
+		if !bytes.Equal(firstOutput, secondOutput) {
+			cases = append(cases, i)
+			if !parser.quiet {
+				log.Printf("Case #%d: nondeterministic output\nFirst run:\n%s\nSecond run:\n%s\n", i, firstOutput, secondOutput)
+			}
+		}
+	}
+	return cases
+}
+
+func (parser *Parser) checkDeterminism() {
+	input, err := readInput(parser.inputFn)
+	if err != nil {
+		log.Fatalln("Error reading input file:", err)
+	}
+	first := parser.openOutput(parser.outputFn)
+	if first == nil {
+		return
+	}
+
+	caseNs := make([]int, 0, len(parser.results))
+	for _, r := range parser.results {
+		caseNs = append(caseNs, r.caseN)
+	}
+	if cases := parser.nondeterministicCases(first, caseNs, input); len(cases) > 0 {
+		log.Printf("%s: nondeterministic output in %d cases\n", parser.inputFn, len(cases))
+		parser.summary.addFailedFile(parser.inputFn)
+	}
+}
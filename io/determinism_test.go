@@ -0,0 +1,28 @@
+package io
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNondeterministicCases(t *testing.T) {
+	runs := 0
+	f := func(input *Input, output *Output) {
+		n := input.Int()
+		if n == 2 {
+			runs++
+			n += runs
+		}
+		output.Print(n)
+	}
+
+	parser := newParser(f, WithDeterminism())
+	parser.quiet = true
+	first := NewCompareOutput(strings.NewReader("Case #1: 1\nCase #2: 2\nCase #3: 3\n"))
+	assert.Equal(t, []int{2}, parser.nondeterministicCases(first, []int{1, 2, 3}, []byte("3\n1\n2\n3\n")))
+	first = NewCompareOutput(strings.NewReader("Case #1: 1\nCase #2: 3\n"))
+	assert.Equal(t, []int{}, parser.nondeterministicCases(first, []int{1, 2}, []byte("2\n1\n3\n")))
+	assert.Equal(t, 1, runs)
+}
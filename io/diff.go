@@ -0,0 +1,134 @@
+package io
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/matematik7/codejam-go/integer"
+)
+
+const (
+	diffContext = 8
+
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorReset = "\x1b[0m"
+)
+
+type diffToken struct {
+	text string
+	line int
+}
+
+func splitTokens(data string) []diffToken {
+	tokens := []diffToken{}
+	line := 1
+	start := -1
+	for i, chr := range data {
+		if unicode.IsSpace(chr) {
+			if start != -1 {
+				tokens = append(tokens, diffToken{data[start:i], line})
+				start = -1
+			}
+			if chr == '\n' {
+				line++
+			}
+		} else if start == -1 {
+			start = i
+		}
+	}
+	if start != -1 {
+		tokens = append(tokens, diffToken{data[start:], line})
+	}
+	return tokens
+}
+
+func exactSame(p, c string) bool {
+	return p == c
+}
+
+func useColor() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	stat, err := os.Stderr.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+func tokenDiff(produced, correct string, same func(p, c string) bool, color bool) string {
+	producedTokens := splitTokens(produced)
+	correctTokens := splitTokens(correct)
+	n := integer.Max(len(producedTokens), len(correctTokens))
+
+	first := -1
+	for i := 0; i < n; i++ {
+		if i >= len(producedTokens) || i >= len(correctTokens) || !same(producedTokens[i].text, correctTokens[i].text) {
+			first = i
+			break
+		}
+	}
+	if first == -1 {
+		return ""
+	}
+
+	line := 0
+	if first < len(correctTokens) {
+		line = correctTokens[first].line
+	} else {
+		line = producedTokens[first].line
+	}
+
+	expectedBuffer := &bytes.Buffer{}
+	producedBuffer := &bytes.Buffer{}
+	from := integer.Max(0, first-diffContext)
+	to := integer.Min(n, first+2*diffContext)
+	if from > 0 {
+		expectedBuffer.WriteString("... ")
+		producedBuffer.WriteString("... ")
+	}
+	for i := from; i < to; i++ {
+		var p, c string
+		if i < len(producedTokens) {
+			p = producedTokens[i].text
+		}
+		if i < len(correctTokens) {
+			c = correctTokens[i].text
+		}
+
+		width := integer.Max(len(p), len(c))
+		differs := i >= len(producedTokens) || i >= len(correctTokens) || !same(p, c)
+		writeDiffToken(expectedBuffer, c, width, differs, color, colorGreen)
+		writeDiffToken(producedBuffer, p, width, differs, color, colorRed)
+	}
+	if to < n {
+		expectedBuffer.WriteString(" ...")
+		producedBuffer.WriteString(" ...")
+	}
+
+	return fmt.Sprintf("First difference at token %d, line %d\nexpected: %s\nproduced: %s",
+		first+1,
+		line,
+		strings.TrimRight(expectedBuffer.String(), " "),
+		strings.TrimRight(producedBuffer.String(), " "),
+	)
+}
+
+func writeDiffToken(buffer *bytes.Buffer, token string, width int, differs, color bool, colorCode string) {
+	padding := strings.Repeat(" ", width-len(token))
+	switch {
+	case differs && color:
+		buffer.WriteString(colorCode + token + colorReset + padding + " ")
+	case differs:
+		buffer.WriteString("[" + token + "]" + padding + " ")
+	case color:
+		buffer.WriteString(token + padding + " ")
+	default:
+		buffer.WriteString(" " + token + " " + padding + " ")
+	}
+}
@@ -0,0 +1,19 @@
+package io
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitTokens(t *testing.T) {
+	assert.Equal(t, []diffToken{{"a", 1}, {"bc", 1}, {"d", 3}}, splitTokens(" a bc\n\nd"))
+	assert.Equal(t, []diffToken{}, splitTokens(" \n"))
+}
+
+func TestTokenDiff(t *testing.T) {
+	assert.Equal(t, "", tokenDiff("1 2\n3", "1 2 3", exactSame, false))
+	assert.Equal(t, "First difference at token 2, line 2\nexpected:  1  [2]\nproduced:  1  [22]", tokenDiff("1\n22", "1\n2", exactSame, false))
+	assert.Equal(t, "First difference at token 2, line 1\nexpected:  1  [2]\nproduced:  1  []", tokenDiff("1", "1 2", exactSame, false))
+	assert.Equal(t, "First difference at token 1, line 1\nexpected: \x1b[32m2\x1b[0m\nproduced: \x1b[31m3\x1b[0m", tokenDiff("3", "2", exactSame, true))
+}
@@ -0,0 +1,96 @@
+package io
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os/exec"
+	"strconv"
+
+	"github.com/matematik7/codejam-go/graph"
+)
+
+const highlightAttrs = "color=red, penwidth=2"
+
+func graphDot(g *graph.Graph, highlights [][]int) []byte {
+	vertices := make(map[int]bool)
+	edges := make(map[[2]int]bool)
+	for _, path := range highlights {
+		for j, v := range path {
+			vertices[v] = true
+			if j > 0 {
+				edges[[2]int{path[j-1], v}] = true
+				if !g.Directed {
+					edges[[2]int{v, path[j-1]}] = true
+				}
+			}
+		}
+	}
+
+	kind, arrow := "graph", "--"
+	if g.Directed {
+		kind, arrow = "digraph", "->"
+	}
+
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "%s G {\n", kind)
+	for v := range g.Adj {
+		if vertices[v] {
+			fmt.Fprintf(b, "\t%d [%s];\n", v, highlightAttrs)
+		} else {
+			fmt.Fprintf(b, "\t%d;\n", v)
+		}
+	}
+	for v, adj := range g.Adj {
+		loops := 0
+		for _, e := range adj {
+			if !g.Directed {
+				if e.To < v {
+					continue
+				}
+				if e.To == v {
+					loops++
+					if loops%2 == 0 {
+						continue
+					}
+				}
+			}
+			attrs := []string{}
+			if e.Weight != 1 {
+				attrs = append(attrs, "label="+strconv.Itoa(e.Weight))
+			}
+			if edges[[2]int{v, e.To}] {
+				attrs = append(attrs, highlightAttrs)
+			}
+			fmt.Fprintf(b, "\t%d %s %d", v, arrow, e.To)
+			for j, attr := range attrs {
+				if j == 0 {
+					b.WriteString(" [")
+				} else {
+					b.WriteString(", ")
+				}
+				b.WriteString(attr)
+			}
+			if len(attrs) > 0 {
+				b.WriteString("]")
+			}
+			b.WriteString(";\n")
+		}
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+func (parser *Parser) saveGraph(dot []byte, i int) {
+	fn := parser.baseFn + strconv.Itoa(i)
+	if err := ioutil.WriteFile(fn+".dot", dot, 0644); err != nil {
+		log.Fatalln("Error writing graph:", err)
+	}
+	if _, err := exec.LookPath("dot"); err != nil {
+		return
+	}
+	if out, err := exec.Command("dot", "-Tsvg", "-o", fn+".svg", fn+".dot").CombinedOutput(); err != nil {
+		log.Fatalln("Error rendering graph:", err, string(out))
+	}
+}
@@ -0,0 +1,28 @@
+package io
+
+import (
+	"testing"
+
+	"github.com/matematik7/codejam-go/graph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphDot(t *testing.T) {
+	g := graph.New(3, false)
+	g.AddEdge(0, 1, 1)
+	g.AddEdge(1, 2, 5)
+	g.AddEdge(2, 2, 1)
+	assert.Equal(t, `graph G {
+	0 [color=red, penwidth=2];
+	1 [color=red, penwidth=2];
+	2;
+	0 -- 1 [color=red, penwidth=2];
+	1 -- 2 [label=5];
+	2 -- 2;
+}
+`, string(graphDot(g, [][]int{{1, 0}})))
+
+	d := graph.New(2, true)
+	d.AddEdge(1, 0, 1)
+	assert.Equal(t, "digraph G {\n\t0;\n\t1;\n\t1 -> 0;\n}\n", string(graphDot(d, nil)))
+}
@@ -0,0 +1,89 @@
+package io
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+func isURL(fn string) bool {
+	return strings.HasPrefix(fn, "http://") || strings.HasPrefix(fn, "https://")
+}
+
+func cachedFn(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		log.Fatalln("Invalid input url:", err)
+	}
+	p := path.Clean("/" + u.Path)
+	if p == "/" {
+		log.Fatalln("Input url has no file name:", rawURL)
+	}
+	dir, fn := path.Split(p)
+	parts := []string{strings.Replace(u.Host, ":", "_", -1)}
+	parts = append(parts, strings.Split(strings.Trim(dir, "/"), "/")...)
+	if u.RawQuery != "" {
+		sum := sha256.Sum256([]byte(u.RawQuery))
+		parts = append(parts, hex.EncodeToString(sum[:4]))
+	}
+	return filepath.Join(append(parts, fn)...)
+}
+
+func download(rawURL, fn string) {
+	resp, err := http.Get(rawURL)
+	if err != nil {
+		log.Fatalln("Error downloading input file:", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Fatalf("Error downloading input file %s: %s\n", rawURL, resp.Status)
+	}
+
+	f, err := os.Create(fn + ".part")
+	if err != nil {
+		log.Fatalln("Error creating input file:", err)
+	}
+	_, err = io.Copy(f, resp.Body)
+	f.Close()
+	if err != nil {
+		os.Remove(fn + ".part")
+		log.Fatalln("Error downloading input file:", err)
+	}
+	if err := os.Rename(fn+".part", fn); err != nil {
+		log.Fatalln("Error saving input file:", err)
+	}
+}
+
+func (parser *Parser) downloadInputs(inputFns []string) []string {
+	fns := make([]string, 0, len(inputFns))
+	for _, inputFn := range inputFns {
+		if !isURL(inputFn) {
+			fns = append(fns, inputFn)
+			continue
+		}
+
+		fn := cachedFn(inputFn)
+		if _, err := os.Stat(fn); err == nil {
+			if !parser.quiet {
+				log.Printf("Using cached %s for %s\n", fn, inputFn)
+			}
+		} else {
+			if err := os.MkdirAll(filepath.Dir(fn), 0755); err != nil {
+				log.Fatalln("Error creating download directory:", err)
+			}
+			download(inputFn, fn)
+			if !parser.quiet {
+				log.Printf("Downloaded %s to %s\n", inputFn, fn)
+			}
+		}
+		fns = append(fns, fn)
+	}
+	return fns
+}
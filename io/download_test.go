@@ -0,0 +1,49 @@
+package io
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedFn(t *testing.T) {
+	assert.Equal(t, filepath.Join("example.com", "a", "A.in"), cachedFn("https://example.com/a/A.in"))
+	assert.NotEqual(t, cachedFn("https://example.com/get?id=1"), cachedFn("https://example.com/get?id=2"))
+}
+
+func TestDownloadInputs(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	defer os.Chdir(wd)
+	assert.NoError(t, os.Chdir(dir))
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("1\n21\n"))
+	}))
+	defer server.Close()
+
+	host := strings.Replace(strings.TrimPrefix(server.URL, "http://"), ":", "_", -1)
+	fn := filepath.Join(host, "round1", "A-large.in")
+	parser := newParser(double, WithArgs(server.URL+"/round1/A-large.in", "B.in"), WithNoProfile())
+	parser.quiet = true
+	assert.Equal(t, []string{fn, "B.in"}, parser.downloadInputs(parser.args))
+	assert.Equal(t, []string{fn}, parser.downloadInputs(parser.args[:1]))
+	assert.Equal(t, 1, requests)
+
+	assert.Equal(t, []string{filepath.Join(host, "round2", "A-large.in")}, parser.downloadInputs([]string{server.URL + "/round2/A-large.in"}))
+	assert.Equal(t, 2, requests)
+
+	data, err := ioutil.ReadFile(fn)
+	assert.NoError(t, err)
+	assert.Equal(t, "1\n21\n", string(data))
+}
@@ -0,0 +1,84 @@
+package io
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/matematik7/codejam-go/integer"
+)
+
+func parseVerdict(str string) (Verdict, bool) {
+	for v, name := range verdictNames {
+		if name == str {
+			return Verdict(v), true
+		}
+	}
+	return Unchecked, false
+}
+
+func readVerdicts(co *CompareOutput) map[int]Verdict {
+	verdicts := make(map[int]Verdict)
+	for caseN, data := range co.outputs {
+		v, ok := parseVerdict(strings.TrimSpace(string(data)))
+		if !ok {
+			log.Fatalln("Invalid verdict for case", caseN, ":", string(data))
+		}
+		verdicts[caseN] = v
+	}
+	return verdicts
+}
+
+func (parser *Parser) loadFailed() {
+	co := openCompareOutput(parser.verdictsFn)
+	if co == nil {
+		log.Fatalln("No previous verdicts, run without -failed first:", parser.verdictsFn)
+	}
+	parser.previousVerdicts = readVerdicts(co)
+	parser.previousOutput = parser.openOutput(parser.outputFn)
+
+	parser.cases = integer.NewSet()
+	for caseN, v := range parser.previousVerdicts {
+		if v.failed() {
+			parser.cases.Insert(caseN)
+		}
+	}
+	log.Println("Running failed cases:", parser.cases.Len())
+}
+
+func (parser *Parser) writePrevious(i int) {
+	if parser.previousOutput == nil || !parser.previousOutput.HasOutput(i) {
+		return
+	}
+	parser.output.init(parser.input, i)
+	parser.output.output.Write(parser.previousOutput.GetOutput(i))
+	parser.output.flush()
+}
+
+func (parser *Parser) writeVerdicts() {
+	verdicts := make(map[int]Verdict)
+	for caseN, v := range parser.previousVerdicts {
+		verdicts[caseN] = v
+	}
+	for _, r := range parser.results {
+		verdicts[r.caseN] = r.verdict
+	}
+
+	caseNs := make([]int, 0, len(verdicts))
+	for caseN := range verdicts {
+		caseNs = append(caseNs, caseN)
+	}
+	sort.Ints(caseNs)
+
+	buffer := &bytes.Buffer{}
+	for _, caseN := range caseNs {
+		fmt.Fprintf(buffer, "Case #%d: %s\n", caseN, verdicts[caseN])
+	}
+
+	if err := ioutil.WriteFile(parser.verdictsFn, buffer.Bytes(), 0644); err != nil {
+		log.Fatalln("Error writing verdicts file:", err)
+	}
+}
@@ -0,0 +1,20 @@
+package io
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadVerdicts(t *testing.T) {
+	co := NewCompareOutput(strings.NewReader("Case #1: OK\nCase #2: WA\nCase #3: -\n"))
+	assert.Equal(t, map[int]Verdict{1: OK, 2: WA, 3: Unchecked}, readVerdicts(co))
+
+	v, ok := parseVerdict("TLE")
+	assert.True(t, ok)
+	assert.Equal(t, TLE, v)
+
+	_, ok = parseVerdict("XX")
+	assert.False(t, ok)
+}
@@ -0,0 +1,175 @@
+package io
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const flameGraphMinWidth = 0.001
+
+const flameGraphHeader = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { font: 12px monospace; margin: 8px; }
+.c { display: flex; }
+.n { display: flex; flex-direction: column-reverse; justify-content: flex-end; overflow: hidden; }
+.l { border: 1px solid #fff; height: 16px; line-height: 16px; white-space: nowrap; overflow: hidden; text-overflow: ellipsis; padding: 0 2px; }
+</style>
+</head>
+<body>
+<h3>%s</h3>
+`
+
+type flameNode struct {
+	name     string
+	value    time.Duration
+	children map[string]*flameNode
+}
+
+func newFlameNode(name string) *flameNode {
+	return &flameNode{
+		name:     name,
+		children: make(map[string]*flameNode),
+	}
+}
+
+func (fn *flameNode) add(stack []string, value time.Duration) {
+	fn.value += value
+	if len(stack) == 0 {
+		return
+	}
+	child, ok := fn.children[stack[0]]
+	if !ok {
+		child = newFlameNode(stack[0])
+		fn.children[stack[0]] = child
+	}
+	child.add(stack[1:], value)
+}
+
+func (fn *flameNode) sortedChildren() []*flameNode {
+	children := make([]*flameNode, 0, len(fn.children))
+	for _, child := range fn.children {
+		children = append(children, child)
+	}
+	sort.Slice(children, func(i, j int) bool {
+		if children[i].value != children[j].value {
+			return children[i].value > children[j].value
+		}
+		return children[i].name < children[j].name
+	})
+	return children
+}
+
+func parseTraces(traces []byte) *flameNode {
+	root := newFlameNode("all")
+
+	var stack []string
+	var value time.Duration
+	flush := func() {
+		if len(stack) == 0 {
+			return
+		}
+		for i, j := 0, len(stack)-1; i < j; i, j = i+1, j-1 {
+			stack[i], stack[j] = stack[j], stack[i]
+		}
+		root.add(stack, value)
+		stack = nil
+	}
+
+	inTrace := false
+	scanner := bufio.NewScanner(bytes.NewReader(traces))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "-----------+") {
+			flush()
+			inTrace = true
+			continue
+		}
+		fields := strings.Fields(line)
+		if !inTrace || len(fields) == 0 {
+			continue
+		}
+		if len(stack) == 0 && len(fields) == 2 {
+			d, err := time.ParseDuration(fields[0])
+			if err != nil {
+				continue
+			}
+			value = d
+			fields = fields[1:]
+		}
+		stack = append(stack, fields[0])
+	}
+	flush()
+	return root
+}
+
+func (fn *flameNode) writeHTML(buffer *bytes.Buffer, parent, total time.Duration) {
+	title := fmt.Sprintf("%s %s (%.2f%%)", fn.name, formatDuration(fn.value.Nanoseconds()), 100*float64(fn.value)/float64(total))
+	fmt.Fprintf(buffer, `<div class="n" style="flex: 0 0 %.4f%%"><div class="l" title="%s" style="background: hsl(%d, 80%%, 60%%)">%s</div>`,
+		100*float64(fn.value)/float64(parent),
+		html.EscapeString(title),
+		flameHue(fn.name),
+		html.EscapeString(fn.name),
+	)
+	buffer.WriteString(`<div class="c">`)
+	for _, child := range fn.sortedChildren() {
+		if float64(child.value)/float64(total) < flameGraphMinWidth {
+			continue
+		}
+		child.writeHTML(buffer, fn.value, total)
+	}
+	buffer.WriteString("</div></div>\n")
+}
+
+func flameHue(name string) int {
+	hash := 0
+	for _, c := range name {
+		hash = (hash*31 + int(c)) % 360
+	}
+	return hash % 60
+}
+
+func renderFlameGraph(root *flameNode, title string) []byte {
+	buffer := &bytes.Buffer{}
+	fmt.Fprintf(buffer, flameGraphHeader, html.EscapeString(title), html.EscapeString(title))
+	buffer.WriteString(`<div class="c">`)
+	if root.value > 0 {
+		root.writeHTML(buffer, root.value, root.value)
+	}
+	buffer.WriteString("</div>\n</body>\n</html>\n")
+	return buffer.Bytes()
+}
+
+func (parser *Parser) flameGraphFn(i int) string {
+	return parser.baseFn + strconv.Itoa(i) + ".html"
+}
+
+func (parser *Parser) writeFlameGraph(i int) {
+	traces, err := exec.Command("go", "tool", "pprof", "-traces", os.Args[0], parser.profileFn).Output()
+	if err != nil {
+		parser.logCase(i, "Not writing flame graph, error running go tool pprof:", err)
+		return
+	}
+
+	fn := parser.flameGraphFn(i)
+	title := fmt.Sprintf("%s case #%d", parser.baseFn, i)
+	if err := ioutil.WriteFile(fn, renderFlameGraph(parseTraces(traces), title), 0644); err != nil {
+		log.Fatalln("Error writing flame graph:", err)
+	}
+	if parser.summary != nil {
+		parser.summary.addFlameGraph(fn)
+	}
+}
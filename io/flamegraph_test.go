@@ -0,0 +1,41 @@
+package io
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testTraces = `File: solution
+Type: cpu
+Duration: 1s, Total samples = 40ms (4%)
+-----------+-------------------------------------------------------
+      30ms   main.fib
+             main.fib
+             main.main
+-----------+-------------------------------------------------------
+      10ms   main.print
+             main.main
+-----------+-------------------------------------------------------
+`
+
+func TestParseTraces(t *testing.T) {
+	root := parseTraces([]byte(testTraces))
+	assert.Equal(t, 40*time.Millisecond, root.value)
+
+	main := root.children["main.main"]
+	assert.Equal(t, 40*time.Millisecond, main.value)
+	assert.Equal(t, 30*time.Millisecond, main.children["main.fib"].value)
+	assert.Equal(t, 30*time.Millisecond, main.children["main.fib"].children["main.fib"].value)
+	assert.Equal(t, 10*time.Millisecond, main.children["main.print"].value)
+	assert.Equal(t, "main.fib", main.sortedChildren()[0].name)
+}
+
+func TestRenderFlameGraph(t *testing.T) {
+	graph := string(renderFlameGraph(parseTraces([]byte(testTraces)), "A <1>"))
+	assert.True(t, strings.Contains(graph, "<title>A &lt;1&gt;</title>"))
+	assert.True(t, strings.Contains(graph, `title="main.print 10.00ms (25.00%)"`))
+	assert.True(t, strings.Contains(graph, "flex: 0 0 25.0000%"))
+}
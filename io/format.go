@@ -0,0 +1,29 @@
+package io
+
+import "log"
+
+const (
+	FormatCases  = "t"
+	FormatSingle = "single"
+	FormatEOF    = "eof"
+)
+
+func (parser *Parser) readCaseCount(input *Input) int {
+	switch parser.format {
+	case "", FormatCases:
+		return input.Int()
+	case FormatSingle:
+		return 1
+	case FormatEOF:
+		return -1
+	}
+	log.Fatalf("Unknown input format %q, use %s, %s or %s\n", parser.format, FormatCases, FormatSingle, FormatEOF)
+	return 0
+}
+
+func moreCases(input *Input, i, T int) bool {
+	if T < 0 {
+		return input.HasNext()
+	}
+	return i <= T
+}
@@ -0,0 +1,39 @@
+package io
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInputFormat(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	inputFn := filepath.Join(dir, "A.in")
+	writeTestFile(t, inputFn, "1\n2\n3\n\n")
+
+	parser := newParser(double, WithArgs(inputFn), WithNoProfile(), WithInputFormat(FormatEOF))
+	parser.Run()
+	out, err := ioutil.ReadFile(filepath.Join(dir, "A.out"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Case #1: 2\nCase #2: 4\nCase #3: 6\n", string(out))
+
+	parser = newParser(double, WithArgs(inputFn), WithNoProfile(), WithInputFormat(FormatSingle))
+	parser.Run()
+	out, err = ioutil.ReadFile(filepath.Join(dir, "A.out"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Case #1: 2\n", string(out))
+}
+
+func TestHasNext(t *testing.T) {
+	i := initInput("1 \n  x\n ")
+	assert.True(t, i.HasNext())
+	assert.Equal(t, 1, i.Int())
+	assert.True(t, i.HasNext())
+	assert.Equal(t, "  x", i.Line())
+	assert.False(t, i.HasNext())
+}
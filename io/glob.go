@@ -0,0 +1,26 @@
+package io
+
+import (
+	"log"
+	"path/filepath"
+	"strings"
+)
+
+func expandGlobs(inputFns []string) []string {
+	expanded := make([]string, 0, len(inputFns))
+	for _, fn := range inputFns {
+		if isURL(fn) || !strings.ContainsAny(fn, "*?[") {
+			expanded = append(expanded, fn)
+			continue
+		}
+		matches, err := filepath.Glob(fn)
+		if err != nil {
+			log.Fatalln("Invalid input file pattern:", fn)
+		}
+		if len(matches) == 0 {
+			log.Fatalln("No input files match", fn)
+		}
+		expanded = append(expanded, matches...)
+	}
+	return expanded
+}
@@ -0,0 +1,25 @@
+package io
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandGlobs(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	writeTestFile(t, filepath.Join(dir, "A.in"), "")
+	writeTestFile(t, filepath.Join(dir, "B.in"), "")
+	writeTestFile(t, filepath.Join(dir, "B.out"), "")
+
+	assert.Equal(t, []string{
+		"C.in",
+		filepath.Join(dir, "A.in"),
+		filepath.Join(dir, "B.in"),
+		"https://example.com/A.in?x=*",
+	}, expandGlobs([]string{"C.in", filepath.Join(dir, "*.in"), "https://example.com/A.in?x=*"}))
+}
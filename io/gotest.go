@@ -0,0 +1,82 @@
+package io
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func GoTest(t *testing.T, f TestCaseFunc, dir string, opts ...Option) {
+	inputFns, err := filepath.Glob(filepath.Join(dir, "*.in"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := false
+	for _, inputFn := range inputFns {
+		parser := newParser(f, opts...)
+		parser.quiet = true
+		parser.SetFn(inputFn)
+		if _, err := os.Stat(parser.correctFn); err != nil {
+			continue
+		}
+		found = true
+
+		t.Run(strings.TrimSuffix(filepath.Base(inputFn), ".in"), func(t *testing.T) {
+			goTestFile(t, parser)
+		})
+	}
+	if !found {
+		t.Fatal("No input files with correct output in", dir)
+	}
+}
+
+func goTestFile(t *testing.T, parser *Parser) {
+	inputF, err := openInput(parser.inputFn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer inputF.Close()
+
+	if !parser.splitsCases() {
+		goTestWholeFile(t, parser, inputF)
+		return
+	}
+
+	parser.compareOutput = parser.openOutput(parser.correctFn)
+	buffer := &bytes.Buffer{}
+	parser.parse(inputF, buffer)
+	produced := parser.newCompareOutput(buffer)
+
+	for _, r := range parser.results {
+		r := r
+		t.Run("case"+strconv.Itoa(r.caseN), func(t *testing.T) {
+			if r.verdict != OK {
+				t.Errorf("verdict %s\nproduced: %s\ncorrect:  %s", r.verdict, produced.GetOutput(r.caseN), parser.compareOutput.GetOutput(r.caseN))
+			}
+		})
+	}
+}
+
+func goTestWholeFile(t *testing.T, parser *Parser, r io.Reader) {
+	correct, err := ioutil.ReadFile(parser.correctFn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := &bytes.Buffer{}
+	parser.parse(r, buffer)
+	for _, r := range parser.results {
+		if r.verdict.failed() {
+			t.Errorf("case %d verdict %s", r.caseN, r.verdict)
+		}
+	}
+	if !parser.equal("", buffer.String(), string(correct)) {
+		t.Errorf("produced:\n%s\ncorrect:\n%s", buffer.String(), correct)
+	}
+}
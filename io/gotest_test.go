@@ -0,0 +1,28 @@
+package io
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGoTest(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	writeTestFile(t, filepath.Join(dir, "A.in"), "2\n1\n2\n")
+	writeTestFile(t, filepath.Join(dir, "A.correct"), "Case #1: 2\nCase #2: 4\n")
+	writeTestFile(t, filepath.Join(dir, "B.in"), "1\n5\n")
+
+	GoTest(t, double, dir)
+}
+
+func TestGoTestNoPrefix(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	writeTestFile(t, filepath.Join(dir, "A.in"), "2\n1\n2\n")
+	writeTestFile(t, filepath.Join(dir, "A.correct"), "2\n4\n")
+
+	GoTest(t, double, dir, WithNoCasePrefix())
+}
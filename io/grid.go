@@ -0,0 +1,146 @@
+package io
+
+import (
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"log"
+	"os"
+	"strconv"
+)
+
+const (
+	gridImageSize  = 800
+	gridFrameDelay = 10
+)
+
+var defaultGridColors = map[byte]color.Color{
+	'.': color.White,
+	' ': color.White,
+	'#': color.Black,
+}
+
+func copyGrid(grid [][]byte) [][]byte {
+	cp := make([][]byte, len(grid))
+	for j, row := range grid {
+		cp[j] = append([]byte(nil), row...)
+	}
+	return cp
+}
+
+func gridColor(c byte, colors map[byte]color.Color) color.Color {
+	if col, ok := colors[c]; ok {
+		return col
+	}
+	if col, ok := defaultGridColors[c]; ok {
+		return col
+	}
+	h := uint32(c) * 2654435761
+	return color.RGBA{R: uint8(h >> 24), G: uint8(h >> 16), B: uint8(h >> 8), A: 255}
+}
+
+func gridSize(grid [][]byte) (int, int) {
+	cols := 0
+	for _, row := range grid {
+		if len(row) > cols {
+			cols = len(row)
+		}
+	}
+	return len(grid), cols
+}
+
+func gridCell(rows, cols int) int {
+	size := rows
+	if cols > size {
+		size = cols
+	}
+	if size == 0 {
+		return 1
+	}
+	cell := gridImageSize / size
+	if cell < 1 {
+		cell = 1
+	}
+	return cell
+}
+
+func gridImage(grid [][]byte, colors map[byte]color.Color) *image.RGBA {
+	rows, cols := gridSize(grid)
+	cell := gridCell(rows, cols)
+	img := image.NewRGBA(image.Rect(0, 0, cols*cell, rows*cell))
+	drawGridCells(img, grid, cell, colors)
+	return img
+}
+
+func drawGridCells(img *image.RGBA, grid [][]byte, cell int, colors map[byte]color.Color) {
+	for y, row := range grid {
+		for x, c := range row {
+			col := gridColor(c, colors)
+			for dy := 0; dy < cell; dy++ {
+				for dx := 0; dx < cell; dx++ {
+					img.Set(x*cell+dx, y*cell+dy, col)
+				}
+			}
+		}
+	}
+}
+
+func (parser *Parser) saveGrid(grid [][]byte, colors map[byte]color.Color, i int) {
+	f, err := os.Create(parser.baseFn + strconv.Itoa(i) + ".grid.png")
+	if err != nil {
+		log.Fatalln("Error creating grid image:", err)
+	}
+	defer f.Close()
+
+	if err := png.Encode(f, gridImage(grid, colors)); err != nil {
+		log.Fatalln("Error saving grid image:", err)
+	}
+}
+
+func gridAnimation(frames [][][]byte, colors map[byte]color.Color) *gif.GIF {
+	rows, cols := 0, 0
+	for _, frame := range frames {
+		r, c := gridSize(frame)
+		if r > rows {
+			rows = r
+		}
+		if c > cols {
+			cols = c
+		}
+	}
+	cell := gridCell(rows, cols)
+	bounds := image.Rect(0, 0, cols*cell, rows*cell)
+
+	anim := &gif.GIF{}
+	for _, frame := range frames {
+		if r, c := gridSize(frame); r == 0 || c == 0 {
+			continue
+		}
+		img := image.NewRGBA(bounds)
+		drawGridCells(img, frame, cell, colors)
+		paletted := image.NewPaletted(bounds, palette.Plan9)
+		draw.Draw(paletted, bounds, img, image.Point{}, draw.Src)
+		anim.Image = append(anim.Image, paletted)
+		anim.Delay = append(anim.Delay, gridFrameDelay)
+	}
+	return anim
+}
+
+func (parser *Parser) saveFrames(frames [][][]byte, colors map[byte]color.Color, i int) {
+	anim := gridAnimation(frames, colors)
+	if len(anim.Image) == 0 {
+		return
+	}
+	f, err := os.Create(parser.baseFn + strconv.Itoa(i) + ".gif")
+	if err != nil {
+		log.Fatalln("Error creating animation:", err)
+	}
+	defer f.Close()
+
+	if err := gif.EncodeAll(f, anim); err != nil {
+		log.Fatalln("Error saving animation:", err)
+	}
+}
@@ -0,0 +1,67 @@
+package io
+
+import (
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGridImage(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	img := gridImage([][]byte{[]byte("#.S"), []byte("..")}, map[byte]color.Color{'S': red})
+	assert.Equal(t, 3*266, img.Bounds().Dx())
+	assert.Equal(t, 2*266, img.Bounds().Dy())
+	assert.Equal(t, color.RGBAModel.Convert(color.Black), img.At(0, 0))
+	assert.Equal(t, color.RGBAModel.Convert(color.White), img.At(266, 0))
+	assert.Equal(t, red, img.At(2*266+5, 5))
+	assert.Equal(t, color.RGBA{}, img.At(2*266, 266))
+	assert.Equal(t, gridColor('x', nil), gridColor('x', nil))
+	assert.NotEqual(t, gridColor('x', nil), gridColor('y', nil))
+}
+
+func TestOutputDrawGrid(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	o := newOutput(nil)
+	o.init(nil, 3)
+	grid := [][]byte{[]byte("#.")}
+	o.DrawGrid(grid, nil)
+	grid[0][0] = '.'
+	assert.Equal(t, byte('#'), o.grid[0][0])
+
+	parser := &Parser{baseFn: filepath.Join(dir, "A")}
+	parser.writeChart(o, 3)
+	_, err := os.Stat(filepath.Join(dir, "A3.grid.png"))
+	assert.NoError(t, err)
+	assert.Nil(t, o.grid)
+}
+
+func TestOutputFrame(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	o := newOutput(nil)
+	o.init(nil, 4)
+	grid := [][]byte{[]byte("#.")}
+	o.Frame(grid, nil)
+	o.Frame(nil, nil)
+	grid[0][0] = '.'
+	o.Frame(grid, nil)
+	o.Frame([][]byte{[]byte("###")}, nil)
+
+	anim := gridAnimation(o.frames, o.palette)
+	assert.Len(t, anim.Image, 3)
+	assert.Equal(t, []int{gridFrameDelay, gridFrameDelay, gridFrameDelay}, anim.Delay)
+	assert.NotEqual(t, anim.Image[0].ColorIndexAt(0, 0), anim.Image[1].ColorIndexAt(0, 0))
+	assert.Equal(t, anim.Image[0].Bounds(), anim.Image[2].Bounds())
+
+	parser := &Parser{baseFn: filepath.Join(dir, "A")}
+	parser.writeChart(o, 4)
+	_, err := os.Stat(filepath.Join(dir, "A4.gif"))
+	assert.NoError(t, err)
+	assert.Nil(t, o.frames)
+}
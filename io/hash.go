@@ -0,0 +1,80 @@
+package io
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+func hashAnswer(answer string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(answer)))
+	return hex.EncodeToString(sum[:])
+}
+
+func (parser *Parser) loadHashes() {
+	parser.caseHashes = nil
+	parser.fileHash = ""
+
+	data, err := ioutil.ReadFile(parser.shaFn)
+	if os.IsNotExist(err) {
+		return
+	}
+	if err != nil {
+		log.Fatalln("Error reading sha file:", err)
+	}
+
+	if bytes.Contains(data, []byte("Case #")) {
+		parser.caseHashes = NewCompareOutput(bytes.NewReader(data))
+		return
+	}
+	parser.fileHash = strings.ToLower(strings.TrimSpace(string(data)))
+}
+
+func (parser *Parser) checkHash(output *Output, i int) Verdict {
+	correctHash := strings.TrimSpace(string(parser.caseHashes.GetOutput(i)))
+	if strings.EqualFold(hashAnswer(string(output.output.Bytes())), correctHash) {
+		return OK
+	}
+	output.Debug("Output hash does not match", parser.shaFn)
+	return WA
+}
+
+func (parser *Parser) checkFileHash(h hash.Hash) {
+	if parser.fileHash == "" || parser.shardCount > 0 {
+		return
+	}
+	if hex.EncodeToString(h.Sum(nil)) == parser.fileHash {
+		log.Println("Output file hash matches", parser.shaFn)
+		return
+	}
+	log.Println("Output file hash does not match", parser.shaFn)
+	parser.summary.addFailedFile(parser.inputFn)
+}
+
+func (parser *Parser) writeHashes() {
+	co := parser.openOutput(parser.outputFn)
+	if co == nil {
+		return
+	}
+
+	caseNs := make([]int, 0, len(co.outputs))
+	for caseN := range co.outputs {
+		caseNs = append(caseNs, caseN)
+	}
+	sort.Ints(caseNs)
+
+	buffer := &bytes.Buffer{}
+	for _, caseN := range caseNs {
+		fmt.Fprintf(buffer, "Case #%d: %s\n", caseN, hashAnswer(string(co.GetOutput(caseN))))
+	}
+	if err := ioutil.WriteFile(parser.shaFn, buffer.Bytes(), 0644); err != nil {
+		log.Fatalln("Error writing sha file:", err)
+	}
+}
@@ -0,0 +1,55 @@
+package io
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashAnswer(t *testing.T) {
+	assert.Equal(t, hashAnswer("4"), hashAnswer(" 4\n"))
+	assert.NotEqual(t, hashAnswer("4"), hashAnswer("5"))
+}
+
+func TestParserCaseHashes(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	inputFn := filepath.Join(dir, "A.in")
+	writeTestFile(t, inputFn, "2\n1\n2\n")
+	writeTestFile(t, filepath.Join(dir, "A.sha"), fmt.Sprintf("Case #1: %s\nCase #2: %s\n", hashAnswer("2"), hashAnswer("5")))
+
+	parser := newParser(double, WithArgs(inputFn), WithNoProfile())
+	parser.Run()
+
+	assert.Equal(t, []caseResult{{caseN: 1, verdict: OK}, {caseN: 2, verdict: WA}}, withoutMeasurements(parser.results))
+}
+
+func TestParserFileHash(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	inputFn := filepath.Join(dir, "A.in")
+	writeTestFile(t, inputFn, "2\n1\n2\n")
+	sum := sha256.Sum256([]byte("Case #1: 2\nCase #2: 4\n"))
+	writeTestFile(t, filepath.Join(dir, "A.sha"), hex.EncodeToString(sum[:])+"\n")
+
+	parser := newParser(double, WithArgs(inputFn), WithNoProfile())
+	parser.Run()
+	assert.False(t, parser.hasFailures())
+
+	writeTestFile(t, filepath.Join(dir, "A.sha"), hashAnswer("wrong"))
+	parser = newParser(double, WithArgs("-writesha", inputFn), WithNoProfile())
+	parser.Run()
+	assert.True(t, parser.hasFailures())
+
+	sha, err := ioutil.ReadFile(filepath.Join(dir, "A.sha"))
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("Case #1: %s\nCase #2: %s\n", hashAnswer("2"), hashAnswer("4")), string(sha))
+}
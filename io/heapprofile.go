@@ -0,0 +1,19 @@
+package io
+
+const maxHeapProfiles = 10
+
+func (parser *Parser) newHeapPeak(memory uint64) bool {
+	if memory <= parser.heapPeak || parser.heapProfiles >= maxHeapProfiles {
+		return false
+	}
+	if parser.memThreshold > 0 && memory <= uint64(parser.memThreshold)<<20 {
+		return false
+	}
+	parser.heapPeak = memory
+	parser.heapProfiles++
+	return true
+}
+
+func (parser *Parser) overMemThreshold() bool {
+	return parser.memThreshold > 0 && heapInUse() > uint64(parser.memThreshold)<<20
+}
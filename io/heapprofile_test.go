@@ -0,0 +1,24 @@
+package io
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHeapPeak(t *testing.T) {
+	parser := &Parser{}
+	assert.True(t, parser.newHeapPeak(10))
+	assert.False(t, parser.newHeapPeak(5))
+	assert.True(t, parser.newHeapPeak(20))
+
+	parser = &Parser{memThreshold: 1}
+	assert.False(t, parser.newHeapPeak(1<<20))
+	assert.True(t, parser.newHeapPeak(2<<20))
+
+	parser = &Parser{}
+	for i := 1; i <= maxHeapProfiles; i++ {
+		assert.True(t, parser.newHeapPeak(uint64(i)))
+	}
+	assert.False(t, parser.newHeapPeak(maxHeapProfiles+1))
+}
@@ -2,9 +2,17 @@ package io
 
 import (
 	"log"
+	"math"
 	"math/big"
+	"math/rand"
+	"runtime"
 	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
 
+	"github.com/matematik7/codejam-go/graph"
 	"github.com/matematik7/codejam-go/integer"
 	"github.com/matematik7/codejam-go/st"
 )
@@ -17,67 +25,269 @@ type InputProvider interface {
 }
 
 type Input struct {
-	scanner InputProvider
-	current []string
+	scanner   InputProvider
+	current   []byte
+	lastStart int
+	position  int64
+	seed      int64
+	rand      *rand.Rand
+	abandoned int32
+	deadline  time.Time
+
+	fn           string
+	returnErrors bool
+	err          error
 }
 
 func newInput(ip InputProvider) *Input {
 	return &Input{
-		scanner: ip,
+		scanner:   ip,
+		lastStart: -1,
 	}
 }
 
-func (i *Input) init() {
+func (i *Input) init(seed int64) {
 	i.current = i.current[:0]
+	i.lastStart = -1
+	i.err = nil
+	i.seed = seed
+	i.rand = nil
+}
+
+func (i *Input) Rand() *rand.Rand {
+	if i.rand == nil {
+		i.rand = rand.New(rand.NewSource(i.seed))
+	}
+	return i.rand
+}
+
+func (i *Input) Deadline() time.Time {
+	return i.deadline
+}
+
+func (i *Input) TimeLeft() time.Duration {
+	if i.deadline.IsZero() {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Until(i.deadline)
 }
 
 func (i *Input) currentCase() []string {
-	return i.current
+	return strings.Fields(string(i.current))
+}
+
+func (i *Input) abandon() {
+	atomic.StoreInt32(&i.abandoned, 1)
 }
 
 func (i *Input) Scan() {
-	if ok := i.scanner.Scan(); !ok {
+	i.scan()
+}
+
+func (i *Input) scan() []byte {
+	return i.scanWith(i.scanner.Scan)
+}
+
+func (i *Input) scanWith(scan func() bool) []byte {
+	if atomic.LoadInt32(&i.abandoned) != 0 {
+		runtime.Goexit()
+	}
+	if ok := scan(); !ok {
 		log.Fatalln("Error scanning input:", i.scanner.Err())
 	}
-	i.current = append(i.current, i.scanner.Text())
+	token := i.scanner.Bytes()
+	if len(i.current) > 0 {
+		i.current = append(i.current, ' ')
+	}
+	i.lastStart = len(i.current)
+	i.current = append(i.current, token...)
+	atomic.AddInt64(&i.position, 1)
+	return token
+}
+
+func (i *Input) Unread() {
+	us, ok := i.scanner.(interface {
+		Unscan() bool
+	})
+	if !ok {
+		log.Fatalln("Input provider does not support unreading")
+	}
+	if i.lastStart < 0 || !us.Unscan() {
+		log.Fatalln("Nothing to unread, only the last token of the current case can be unread once")
+	}
+	end := i.lastStart - 1
+	if end < 0 {
+		end = 0
+	}
+	i.current = i.current[:end]
+	i.lastStart = -1
+	atomic.AddInt64(&i.position, -1)
+}
+
+func (i *Input) HasNext() bool {
+	hn, ok := i.scanner.(interface {
+		HasNext() bool
+	})
+	if !ok {
+		log.Fatalln("Input provider does not support checking for more input")
+	}
+	return hn.HasNext()
+}
+
+func (i *Input) Peek() string {
+	token := i.String()
+	i.Unread()
+	return token
 }
 
 func (i *Input) String() string {
-	i.Scan()
-	return i.scanner.Text()
+	return string(i.scan())
+}
+
+func (i *Input) Line() string {
+	lp, ok := i.scanner.(interface {
+		ScanLine() bool
+	})
+	if !ok {
+		log.Fatalln("Input provider does not support reading lines")
+	}
+	return string(i.scanWith(lp.ScanLine))
+}
+
+func (i *Input) Byte() byte {
+	bs, ok := i.scanner.(interface {
+		ScanByte() bool
+	})
+	if !ok {
+		log.Fatalln("Input provider does not support reading bytes")
+	}
+	return i.scanWith(bs.ScanByte)[0]
+}
+
+func (i *Input) Rune() rune {
+	rs, ok := i.scanner.(interface {
+		ScanRune() bool
+	})
+	if !ok {
+		log.Fatalln("Input provider does not support reading runes")
+	}
+	r, _ := utf8.DecodeRune(i.scanWith(rs.ScanRune))
+	return r
+}
+
+func (i *Input) Delimited(delims string) string {
+	ds, ok := i.scanner.(interface {
+		ScanDelimited(delims string) bool
+	})
+	if !ok {
+		log.Fatalln("Input provider does not support delimiters")
+	}
+	return string(i.scanWith(func() bool {
+		return ds.ScanDelimited(delims)
+	}))
+}
+
+func (i *Input) Fixed(width int) string {
+	fs, ok := i.scanner.(interface {
+		ScanFixed(width int) bool
+	})
+	if !ok {
+		log.Fatalln("Input provider does not support fixed width fields")
+	}
+	return string(i.scanWith(func() bool {
+		return fs.ScanFixed(width)
+	}))
 }
 
 func (i *Input) Bytes() []byte {
-	i.Scan()
-	data := i.scanner.Bytes()
+	data := i.scan()
 	data_copy := make([]byte, len(data))
 	copy(data_copy, data)
 	return data_copy
 }
 
 func (i *Input) Int() int {
-	n, err := strconv.Atoi(i.String())
+	token := i.scan()
+	if n, ok := parseInt(token); ok {
+		return n
+	}
+	n, err := strconv.Atoi(string(token))
 	if err != nil {
-		log.Fatalln("Error scanning for int:", err)
+		i.fail(token, "int", err)
 	}
 	return n
 }
 
+func (i *Input) IntBase(base int) int {
+	token := i.scan()
+	n, err := strconv.ParseInt(string(token), base, 0)
+	if err != nil {
+		i.fail(token, "int in base "+strconv.Itoa(base), err)
+	}
+	return int(n)
+}
+
+func (i *Input) Bits() int {
+	token := i.scan()
+	if len(token) > 63 {
+		i.fail(token, "bits", strconv.ErrRange)
+		return 0
+	}
+	mask := 0
+	for j, c := range token {
+		switch c {
+		case '1':
+			mask |= 1 << uint(j)
+		case '0':
+		default:
+			i.fail(token, "bits", nil)
+			return 0
+		}
+	}
+	return mask
+}
+
 func (i *Input) Float() float64 {
-	f, err := strconv.ParseFloat(i.String(), 64)
+	token := i.scan()
+	f, err := strconv.ParseFloat(string(token), 64)
 	if err != nil {
-		log.Fatalln("Error scanning for float:", err)
+		i.fail(token, "float", err)
 	}
 	return f
 }
 
+func (i *Input) Int64() int64 {
+	token := i.scan()
+	if n, ok := parseInt(token); ok {
+		return int64(n)
+	}
+	n, err := strconv.ParseInt(string(token), 10, 64)
+	if err != nil {
+		i.fail(token, "int64", err)
+	}
+	return n
+}
+
+func (i *Input) Uint64() uint64 {
+	token := i.scan()
+	n, err := strconv.ParseUint(string(token), 10, 64)
+	if err != nil {
+		i.fail(token, "uint64", err)
+	}
+	return n
+}
+
+func (i *Input) Float64() float64 {
+	return i.Float()
+}
+
 func (i *Input) BigInt() *big.Int {
 	n := &big.Int{}
 	str := i.String()
 
-	n, ok := n.SetString(str, 10)
-	if !ok {
-		log.Fatalln("Error scanning for big int:", str)
+	if _, ok := n.SetString(str, 10); !ok {
+		i.fail([]byte(str), "big int", nil)
+		return &big.Int{}
 	}
 	return n
 }
@@ -87,7 +297,8 @@ func (i *Input) Digits() []int {
 	ints := make([]int, 0, len(str))
 	for _, chr := range str {
 		if chr < 48 || chr > 57 {
-			log.Fatalln("String element not a digit:", chr)
+			i.fail([]byte(str), "digits", nil)
+			return nil
 		}
 		ints = append(ints, int(chr-48))
 	}
@@ -102,6 +313,30 @@ func (i *Input) SliceInt(n int) integer.Slice {
 	return ints
 }
 
+func (i *Input) SliceInt64(n int) []int64 {
+	ints := make([]int64, 0, n)
+	for j := 0; j < n; j++ {
+		ints = append(ints, i.Int64())
+	}
+	return ints
+}
+
+func (i *Input) Ints(n int) []int {
+	return i.SliceInt(n)
+}
+
+func (i *Input) Int64s(n int) []int64 {
+	return i.SliceInt64(n)
+}
+
+func (i *Input) SliceUint64(n int) []uint64 {
+	uints := make([]uint64, 0, n)
+	for j := 0; j < n; j++ {
+		uints = append(uints, i.Uint64())
+	}
+	return uints
+}
+
 func (i *Input) SetInt(n int) *integer.Set {
 	return integer.NewSet(i.SliceInt(n)...)
 }
@@ -130,6 +365,44 @@ func (i *Input) GridInt(y, x int) integer.Grid {
 	return grid
 }
 
+func (i *Input) IntMatrix(rows, cols int) [][]int {
+	return i.GridInt(rows, cols)
+}
+
+func (i *Input) Grid(rows int) [][]byte {
+	return i.SliceBytes(rows)
+}
+
+func (i *Input) Graph(n, m int, directed bool) *graph.Graph {
+	g := graph.New(n, directed)
+	for j := 0; j < m; j++ {
+		from, to := i.Int()-1, i.Int()-1
+		g.AddEdge(from, to, 1)
+	}
+	return g
+}
+
+func (i *Input) WeightedGraph(n, m int, directed bool) *graph.Graph {
+	g := graph.New(n, directed)
+	for j := 0; j < m; j++ {
+		from, to := i.Int()-1, i.Int()-1
+		g.AddEdge(from, to, i.Int())
+	}
+	return g
+}
+
+func (i *Input) TreeParents(n int) *graph.Graph {
+	g := graph.New(n, true)
+	for v := 1; v < n; v++ {
+		g.AddEdge(i.Int()-1, v, 1)
+	}
+	return g
+}
+
+func (i *Input) TreeEdges(n int) *graph.Graph {
+	return i.Graph(n, n-1, false)
+}
+
 func (i *Input) SliceFloat(n int) []float64 {
 	floats := make([]float64, 0, n)
 	for j := 0; j < n; j++ {
@@ -138,6 +411,14 @@ func (i *Input) SliceFloat(n int) []float64 {
 	return floats
 }
 
+func (i *Input) Floats(n int) []float64 {
+	return i.SliceFloat(n)
+}
+
+func (i *Input) SliceFloat64(n int) []float64 {
+	return i.SliceFloat(n)
+}
+
 func (i *Input) GridFloat(y, x int) [][]float64 {
 	grid := make([][]float64, 0, y)
 	for j := 0; j < y; j++ {
@@ -154,6 +435,10 @@ func (i *Input) SliceString(n int) []string {
 	return strs
 }
 
+func (i *Input) Strings(n int) []string {
+	return i.SliceString(n)
+}
+
 func (i *Input) SliceBytes(n int) [][]byte {
 	sb := make([][]byte, n)
 	for j := range sb {
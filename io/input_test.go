@@ -1,20 +1,19 @@
 package io
 
 import (
-	"bufio"
 	"math/big"
+	"strconv"
 	"strings"
 	"testing"
 
+	"github.com/matematik7/codejam-go/graph"
 	"github.com/matematik7/codejam-go/integer"
 	"github.com/matematik7/codejam-go/st"
 	"github.com/stretchr/testify/assert"
 )
 
 func initInput(s string) *Input {
-	scanner := bufio.NewScanner(strings.NewReader(s))
-	scanner.Split(bufio.ScanWords)
-	return newInput(scanner)
+	return newInput(NewTokenizer(strings.NewReader(s)))
 }
 
 func TestString(t *testing.T) {
@@ -24,6 +23,33 @@ func TestString(t *testing.T) {
 	assert.Equal(t, "input3", i.String())
 }
 
+func TestPeekUnread(t *testing.T) {
+	i := initInput("3 end 5")
+	assert.Equal(t, "3", i.Peek())
+	assert.Equal(t, "3", i.Peek())
+	assert.Equal(t, 3, i.Int())
+
+	assert.Equal(t, "end", i.String())
+	i.Unread()
+	assert.Equal(t, []string{"3"}, i.currentCase())
+	assert.Equal(t, "end", i.String())
+	assert.Equal(t, 5, i.Int())
+	assert.Equal(t, []string{"3", "end", "5"}, i.currentCase())
+	assert.Equal(t, int64(3), i.position)
+}
+
+func TestPeekOtherScans(t *testing.T) {
+	i := initInput("ab cd\nxyz\néa")
+	assert.Equal(t, "ab", i.Peek())
+	assert.Equal(t, "ab cd", i.Line())
+	assert.Equal(t, "xyz", i.Peek())
+	assert.Equal(t, byte('x'), i.Byte())
+	assert.Equal(t, "yz", i.String())
+	assert.Equal(t, "éa", i.Peek())
+	assert.Equal(t, 'é', i.Rune())
+	assert.Equal(t, "a", i.String())
+}
+
 func TestBytes(t *testing.T) {
 	i := initInput("input1 input2\ninput3")
 	assert.Equal(t, []byte("input1"), i.Bytes())
@@ -45,6 +71,25 @@ func TestFloat(t *testing.T) {
 	assert.Equal(t, 1e15, i.Float())
 }
 
+func TestInt64(t *testing.T) {
+	i := initInput("1 -1000000000000000000 9223372036854775807")
+	assert.Equal(t, int64(1), i.Int64())
+	assert.Equal(t, int64(-1000000000000000000), i.Int64())
+	assert.Equal(t, int64(9223372036854775807), i.Int64())
+}
+
+func TestUint64(t *testing.T) {
+	i := initInput("0 18446744073709551615")
+	assert.Equal(t, uint64(0), i.Uint64())
+	assert.Equal(t, uint64(18446744073709551615), i.Uint64())
+}
+
+func TestFloat64(t *testing.T) {
+	i := initInput("0.5 -2e-9")
+	assert.Equal(t, 0.5, i.Float64())
+	assert.Equal(t, -2e-9, i.Float64())
+}
+
 func TestBigInt(t *testing.T) {
 	i := initInput("1 -123\n1000000")
 	assert.Equal(t, big.NewInt(1), i.BigInt())
@@ -103,11 +148,62 @@ func TestGridInt(t *testing.T) {
 	assert.Equal(t, integer.Grid([][]int{[]int{1, 2, 3}, []int{4, 5, 6}}), i.GridInt(2, 3))
 }
 
+func TestIntMatrix(t *testing.T) {
+	i := initInput("1 2 3\n4 5 6")
+	assert.Equal(t, [][]int{{1, 2, 3}, {4, 5, 6}}, i.IntMatrix(2, 3))
+}
+
+func TestGrid(t *testing.T) {
+	i := initInput("#..\n.#.\n")
+	grid := i.Grid(2)
+	assert.Equal(t, [][]byte{[]byte("#.."), []byte(".#.")}, grid)
+	grid[0][0] = '.'
+	assert.Equal(t, byte('.'), grid[0][0])
+}
+
+func TestGraph(t *testing.T) {
+	i := initInput("1 2\n2 3\n1 2 7\n")
+	g := i.Graph(3, 2, false)
+	assert.Equal(t, []graph.Edge{{To: 1, Weight: 1}}, g.Adj[0])
+	assert.Equal(t, []int{0, 1, 2}, g.BFS(0))
+
+	w := i.WeightedGraph(2, 1, true)
+	assert.Equal(t, []graph.Edge{{To: 1, Weight: 7}}, w.Adj[0])
+	assert.Equal(t, 0, len(w.Adj[1]))
+}
+
+func TestTree(t *testing.T) {
+	i := initInput("1 1 2\n1 2\n2 3\n")
+	parents := i.TreeParents(4)
+	assert.Equal(t, []graph.Edge{{To: 1, Weight: 1}, {To: 2, Weight: 1}}, parents.Adj[0])
+	assert.Equal(t, []graph.Edge{{To: 3, Weight: 1}}, parents.Adj[1])
+	assert.Equal(t, 0, len(parents.Adj[3]))
+
+	edges := i.TreeEdges(3)
+	assert.Equal(t, []int{0, 1, 2}, edges.BFS(0))
+	assert.Equal(t, 2, len(edges.Adj[1]))
+}
+
 func TestSliceFloat(t *testing.T) {
 	i := initInput("1.0 -123.1\n1e15")
 	assert.Equal(t, []float64{1.0, -123.1, 1e15}, i.SliceFloat(3))
 }
 
+func TestSlice64(t *testing.T) {
+	i := initInput("1 -2 3 18446744073709551615 1.5 -0.5")
+	assert.Equal(t, []int64{1, -2}, i.SliceInt64(2))
+	assert.Equal(t, []uint64{3, 18446744073709551615}, i.SliceUint64(2))
+	assert.Equal(t, []float64{1.5, -0.5}, i.SliceFloat64(2))
+}
+
+func TestBulkReaders(t *testing.T) {
+	i := initInput("3 1 2 3\n2 -4 1000000000000000000\n1 0.25\n2 a b")
+	assert.Equal(t, []int{1, 2, 3}, i.Ints(i.Int()))
+	assert.Equal(t, []int64{-4, 1000000000000000000}, i.Int64s(i.Int()))
+	assert.Equal(t, []float64{0.25}, i.Floats(i.Int()))
+	assert.Equal(t, []string{"a", "b"}, i.Strings(i.Int()))
+}
+
 func TestGridFloat(t *testing.T) {
 	i := initInput("1.1 2.2 3.3\n4.4 5.5 6.6")
 	assert.Equal(t, [][]float64{[]float64{1.1, 2.2, 3.3}, []float64{4.4, 5.5, 6.6}}, i.GridFloat(2, 3))
@@ -117,3 +213,41 @@ func TestSliceString(t *testing.T) {
 	i := initInput("input1 input2\ninput3")
 	assert.Equal(t, []string{"input1", "input2", "input3"}, i.SliceString(3))
 }
+
+func TestRand(t *testing.T) {
+	i := initInput("")
+	i.init(5)
+	first := i.Rand().Int63()
+	assert.Equal(t, i.Rand(), i.Rand())
+	i.init(5)
+	assert.Equal(t, first, i.Rand().Int63())
+	i.init(6)
+	assert.NotEqual(t, first, i.Rand().Int63())
+}
+
+func TestParseError(t *testing.T) {
+	i := initInput("1 2\n  x3 4.5z")
+	i.fn = "A-small.in"
+	i.returnErrors = true
+	assert.Equal(t, 1, i.Int())
+	assert.Equal(t, 2, i.Int())
+	assert.Equal(t, 0, i.Int())
+	assert.Equal(t, 0.0, i.Float())
+
+	pe, ok := i.Err().(*ParseError)
+	assert.True(t, ok)
+	assert.Equal(t, &ParseError{File: "A-small.in", Offset: 6, Line: 2, Token: 3, Text: "x3", Type: "int", Err: strconv.ErrSyntax}, pe)
+	assert.Equal(t, `A-small.in:2: token 3 at byte 6: cannot parse "x3" as int: invalid syntax`, pe.Error())
+
+	i.init(0)
+	assert.NoError(t, i.Err())
+}
+
+func TestIntBase(t *testing.T) {
+	i := initInput("ff -101 0x1f 0b11 0110")
+	assert.Equal(t, 255, i.IntBase(16))
+	assert.Equal(t, -5, i.IntBase(2))
+	assert.Equal(t, 31, i.IntBase(0))
+	assert.Equal(t, 3, i.IntBase(0))
+	assert.Equal(t, 6, i.Bits())
+}
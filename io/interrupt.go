@@ -0,0 +1,36 @@
+package io
+
+import (
+	"log"
+	"os"
+	"os/signal"
+)
+
+func (parser *Parser) handleInterrupt() func() {
+	parser.interrupted = make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	go func(interrupted chan struct{}) {
+		if _, ok := <-signals; !ok {
+			return
+		}
+		log.Println("Interrupted, stopping the current case, press Ctrl-C again to exit immediately")
+		close(interrupted)
+		if _, ok := <-signals; ok {
+			os.Exit(130)
+		}
+	}(parser.interrupted)
+	return func() {
+		signal.Stop(signals)
+		close(signals)
+	}
+}
+
+func (parser *Parser) isInterrupted() bool {
+	select {
+	case <-parser.interrupted:
+		return true
+	default:
+		return false
+	}
+}
@@ -2,60 +2,255 @@ package io
 
 import (
 	"bufio"
+	"crypto/sha256"
 	"fmt"
+	"io"
 	"log"
 	"os"
-	"os/exec"
-	"runtime/pprof"
-	"strconv"
+	"runtime/debug"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/gonum/plot"
-	"github.com/gonum/plot/plotutil"
-	"github.com/gonum/plot/vg"
+	"github.com/matematik7/codejam-go/integer"
 )
 
 type TestCaseFunc func(*Input, *Output)
 
+var defaultCorrectExts = []string{".correct", ".ans", ".expected", ".out.ok"}
+
 type Parser struct {
 	f             TestCaseFunc
+	args          []string
+	configFn      string
+	inputFns      []string
 	input         *Input
 	output        *Output
 	compareOutput *CompareOutput
 
-	baseFn    string
-	inputFn   string
-	outputFn  string
-	correctFn string
-	profileFn string
+	baseFn      string
+	inputFn     string
+	outputFn    string
+	correctFn   string
+	correctExts []string
+	profileFn   string
+	verdictsFn  string
+	shaFn       string
+	debugJSONFn string
+
+	interactive  bool
+	quiet        bool
+	verbosity    int
+	period       time.Duration
+	noProfile    bool
+	profileStart time.Duration
+	profileStop  time.Duration
+	memProfile   bool
+	memThreshold int
+	heapPeak     uint64
+	heapProfiles int
+	gcPercent    int
+	gcBetween    bool
+	parallelism  int
+	judgeCmd     string
+	recordFn     string
+	replayFn     string
+	checker      Checker
+
+	yes         string
+	no          string
+	possible    string
+	impossible  string
+	precision   int
+	casePrefix  string
+	prefixLine  bool
+	noPrefix    bool
+	raw         bool
+	autoFlush   bool
+	tee         string
+	caseOutputs bool
+
+	chartWidth  float64
+	chartHeight float64
+	chartFormat string
+	timingChart bool
+	pointsCSV   bool
+
+	absTolerance float64
+	relTolerance float64
+	anyLineOrder bool
+	normalizers  []Normalizer
+	generator    GeneratorFunc
+	brute        TestCaseFunc
+	referenceCmd string
+
+	timeLimit     time.Duration
+	fileTimeLimit time.Duration
+	fileDeadline  time.Time
+	skipTLE       bool
+
+	sandbox      bool
+	sandboxChild bool
+	cpuLimit     time.Duration
+	memLimit     int
+
+	cases      *integer.Set
+	failedOnly bool
+	shardIndex int
+	shardCount int
+
+	previousOutput   *CompareOutput
+	previousVerdicts map[int]Verdict
+
+	caseHashes *CompareOutput
+	fileHash   string
+	writeSha   bool
+
+	seed         int64
+	determinism  bool
+	benchRuns    int
+	validator    ValidateFunc
+	validateOnly bool
+	parseErrors  bool
+	format       string
+	delimiters   string
+	strict       bool
+	bufferSize   int
+
+	stressIterations int
+	stressCases      int
+	stressShrink     bool
+	mergeFn          string
+
+	runner      *runner
+	interrupted chan struct{}
+	results     []caseResult
+	summary     *summary
+	showSummary bool
+	slowest     int
+	progress    bool
+	notify      string
+	notifier    *notifier
+	reportFn    string
+	httpAddr    string
+	dashboard   *dashboard
+	archiveDir  string
 }
 
-func TestCases(f TestCaseFunc) {
+func TestCases(f TestCaseFunc, opts ...Option) {
 	log.SetFlags(0)
 
-	parser := Parser{
-		f: f,
+	parser := newParser(f, opts...)
+	parser.Run()
+	if parser.isInterrupted() {
+		os.Exit(130)
+	}
+	if parser.hasFailures() {
+		os.Exit(1)
 	}
+}
+
+func Run(f TestCaseFunc, in io.Reader, out io.Writer, opts ...Option) {
+	parser := newParser(f, append([]Option{WithArgs(), WithNoProfile()}, opts...)...)
+	parser.quiet = true
+	parser.showSummary = false
+	parser.baseFn = "run"
+
+	w := bufio.NewWriter(out)
+	defer w.Flush()
+
+	parser.parse(in, w)
+}
 
-	if len(os.Args) < 2 {
-		log.Fatalln("You need to specify at least one input file")
+func newParser(f TestCaseFunc, opts ...Option) *Parser {
+	parser := &Parser{
+		f:            f,
+		args:         os.Args[1:],
+		parallelism:  1,
+		profileStart: time.Second,
+		profileStop:  10 * time.Second,
+		precision:    -1,
+		stressCases:  1,
+		stressShrink: true,
+		verbosity:    1,
+		seed:         time.Now().UnixNano(),
+		summary:      &summary{},
+		showSummary:  true,
+		slowest:      5,
 	}
-	for _, inputFn := range os.Args[1:] {
-		parser.SetFn(inputFn)
-		parser.ParseFile()
+	for _, opt := range opts {
+		opt(parser)
 	}
+	return parser
+}
+
+func (parser *Parser) ParseFiles(inputFns []string) {
+	if parser.parallelism <= 1 {
+		for _, inputFn := range inputFns {
+			if parser.isInterrupted() {
+				return
+			}
+			parser.SetFn(inputFn)
+			parser.ParseFile()
+		}
+		return
+	}
+
+	wg := sync.WaitGroup{}
+	sem := make(chan struct{}, parser.parallelism)
+	for _, inputFn := range inputFns {
+		fileParser := *parser
+		fileParser.results = nil
+		fileParser.SetFn(inputFn)
+
+		sem <- struct{}{}
+		if parser.isInterrupted() {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fileParser.ParseFile()
+			<-sem
+		}()
+	}
+	wg.Wait()
 }
 
 func (parser *Parser) SetFn(inputFn string) {
 	parser.inputFn = inputFn
-	parser.baseFn = strings.TrimSuffix(inputFn, ".in")
-	parser.outputFn = parser.baseFn + ".out"
-	parser.correctFn = parser.baseFn + ".correct"
+	parser.baseFn = strings.TrimSuffix(trimCompression(inputFn), ".in")
+	parser.outputFn = parser.baseFn + parser.shardSuffix() + ".out"
+	parser.correctFn = parser.findCorrectFn()
 	parser.profileFn = parser.baseFn + ".prof"
+	parser.verdictsFn = parser.baseFn + parser.shardSuffix() + ".verdicts"
+	parser.shaFn = parser.baseFn + ".sha"
+	parser.debugJSONFn = parser.baseFn + parser.shardSuffix() + ".debug.jsonl"
+}
+
+func (parser *Parser) caseSeed(i int) int64 {
+	return parser.seed + int64(i)
+}
+
+func (parser *Parser) caseDeadline(start time.Time) time.Time {
+	deadline := parser.fileDeadline
+	if parser.timeLimit > 0 {
+		caseDeadline := start.Add(parser.timeLimit)
+		if deadline.IsZero() || caseDeadline.Before(deadline) {
+			deadline = caseDeadline
+		}
+	}
+	return deadline
+}
+
+func (parser *Parser) periodInterval() time.Duration {
+	if parser.period <= 0 {
+		return time.Second
+	}
+	return parser.period
 }
 
-func (parser *Parser) formatDuration(d int64) string {
+func formatDuration(d int64) string {
 	var i int
 	df := float64(d)
 	units := []string{"ns", "us", "ms", "s"}
@@ -69,128 +264,232 @@ func (parser *Parser) formatDuration(d int64) string {
 	return res
 }
 
+func openCompareOutput(fn string) *CompareOutput {
+	return openCompareOutputWith(fn, NewCompareOutput)
+}
+
+func (parser *Parser) openOutput(fn string) *CompareOutput {
+	return openCompareOutputWith(fn, parser.newCompareOutput)
+}
+
+func openCompareOutputWith(fn string, newCompareOutput func(io.Reader) *CompareOutput) *CompareOutput {
+	if _, err := os.Stat(fn); err != nil {
+		return nil
+	}
+
+	f, err := os.Open(fn)
+	if err != nil {
+		log.Fatalln("Error opening file:", err)
+	}
+	defer f.Close()
+
+	return newCompareOutput(f)
+}
+
+func (parser *Parser) findCorrectFn() string {
+	exts := parser.correctExts
+	if len(exts) == 0 {
+		exts = defaultCorrectExts
+	}
+	for _, ext := range exts {
+		if _, err := os.Stat(parser.baseFn + ext); err == nil {
+			return parser.baseFn + ext
+		}
+	}
+	return parser.baseFn + exts[0]
+}
+
 func (parser *Parser) ParseFile() {
-	inputF, err := os.Open(parser.inputFn)
+	if parser.sandbox {
+		parser.ParseSandboxed()
+		return
+	}
+
+	inputF, err := openInput(parser.inputFn)
 	if err != nil {
 		log.Fatalln("Error opening input file:", err)
 	}
 	defer inputF.Close()
 
-	outputF, err := os.Create(parser.outputFn)
+	parser.previousOutput = nil
+	parser.previousVerdicts = nil
+	if parser.failedOnly {
+		parser.loadFailed()
+	}
+
+	outputF, err := createAtomic(parser.outputFn)
 	if err != nil {
 		log.Fatalln("Error creating output file:", err)
 	}
 	defer outputF.Close()
 
-	scanner := bufio.NewScanner(inputF)
-	scanner.Split(bufio.ScanWords)
-
-	parser.output = newOutput(outputF)
-	parser.input = newInput(scanner)
-
 	parser.compareOutput = nil
-	if _, err := os.Stat(parser.correctFn); err == nil {
-		correctF, err := os.Open(parser.correctFn)
-		if err != nil {
-			log.Fatalln("Error opening correct file:", err)
-		}
-		defer correctF.Close()
-
-		parser.compareOutput = NewCompareOutput(correctF)
+	if parser.referenceCmd != "" {
+		parser.compareOutput = parser.referenceOutput()
+	} else if parser.splitsCases() {
+		parser.compareOutput = parser.openOutput(parser.correctFn)
 	}
+	parser.loadHashes()
 
-	T := parser.input.Int()
-
-	startTime := time.Now().UnixNano()
-	for i := 1; i <= T; i++ {
-		parser.runTestCase(i)
+	outputHash := sha256.New()
+	writers := []io.Writer{outputF, outputHash}
+	if tee := parser.teeWriter(); tee != nil {
+		writers = append(writers, tee)
+	}
+	parser.parse(inputF, io.MultiWriter(writers...))
+	if parser.isInterrupted() {
+		log.Println("Partial output written to", outputF.Name())
+		return
+	}
+	if err := outputF.commit(); err != nil {
+		log.Fatalln("Error writing output file:", err)
+	}
+	parser.writeVerdicts()
+	if !parser.splitsCases() && parser.referenceCmd == "" {
+		parser.checkWholeOutput()
+	}
+	parser.checkTotals()
+	parser.checkFileHash(outputHash)
+	if parser.determinism {
+		parser.checkDeterminism()
+	}
+	if parser.writeSha {
+		parser.writeHashes()
 	}
-	log.Println("Total time:", parser.formatDuration(time.Now().UnixNano()-startTime))
 }
 
-func (parser *Parser) runTestCase(i int) {
-	warningTimer := time.NewTimer(500 * time.Millisecond)
-	startProfileTimer := time.NewTimer(1 * time.Second)
-	stopProfileTimer := time.NewTimer(10 * time.Second)
-	periodicPrintTicker := time.NewTicker(1 * time.Second)
+func (parser *Parser) teeWriter() io.Writer {
+	switch parser.tee {
+	case "":
+		return nil
+	case "stdout":
+		return os.Stdout
+	case "stderr":
+		return os.Stderr
+	}
+	log.Fatalf("Unknown tee target %q, use stdout or stderr\n", parser.tee)
+	return nil
+}
 
-	doneChan := make(chan bool)
+func (parser *Parser) ParseInteractive() {
+	parser.interactive = true
+	parser.baseFn = "interactive"
+	parser.profileFn = parser.baseFn + ".prof"
+	parser.compareOutput = nil
 
-	go func() {
-		parser.output.init(parser.input, i)
-		parser.input.init()
+	if parser.recordFn == "" {
+		parser.parse(os.Stdin, os.Stdout)
+		return
+	}
+	t := openTranscript(parser.recordFn)
+	defer t.close()
+	parser.parse(io.TeeReader(os.Stdin, newTranscriptWriter(t, judgePrefix)), io.MultiWriter(os.Stdout, newTranscriptWriter(t, solutionPrefix)))
+}
 
-		parser.f(parser.input, parser.output)
+func (parser *Parser) ParseStdin() {
+	parser.quiet = true
+	parser.baseFn = "stdin"
+	parser.profileFn = parser.baseFn + ".prof"
+	parser.compareOutput = nil
 
-		if parser.compareOutput != nil && parser.compareOutput.HasOutput(i) {
-			parser.output.AssertEqual(string(parser.compareOutput.GetOutput(i)))
-		}
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
 
-		parser.output.flush()
-		parser.writeChart(i)
-		doneChan <- true
-	}()
-
-	var f *os.File
-	var err error
-
-loop:
-	for {
-		select {
-		case <-warningTimer.C:
-			parser.output.Debug("Long calculation")
-		case <-startProfileTimer.C:
-			f, err = os.Create(parser.profileFn)
-			if err != nil {
-				log.Fatalln("Error opening profile file:", err)
-			}
-			pprof.StartCPUProfile(f)
-		case <-stopProfileTimer.C:
-			pprof.StopCPUProfile()
-			f = nil
-			out, err := exec.Command("go", "tool", "pprof", "-top", os.Args[0], parser.profileFn).CombinedOutput()
-			if err != nil {
-				log.Fatalln("Error running profile tool:", err)
-			}
-			parser.output.Debug("CPUProfile:", string(out))
+	parser.parse(os.Stdin, w)
+}
 
-			err = exec.Command("go", "tool", "pprof", "-web", os.Args[0], parser.profileFn).Start()
-			if err != nil {
-				log.Fatalln("Error running profile tool:", err)
-			}
-		case <-periodicPrintTicker.C:
-			parser.output.triggerPeriodic()
-		case <-doneChan:
-			break loop
-		}
+func (parser *Parser) parse(r io.Reader, w io.Writer) {
+	scanner := NewTokenizer(r)
+	if parser.bufferSize > 0 {
+		scanner = newTokenizerSize(r, parser.bufferSize)
 	}
-
-	periodicPrintTicker.Stop()
-	parser.output.resetPeriodic()
-	if f != nil {
-		pprof.StopCPUProfile()
+	scanner.SetDelimiters(parser.delimiters)
+
+	parser.output = newOutput(w)
+	parser.output.interactive = parser.interactive
+	parser.output.quiet = parser.quiet
+	parser.output.verbosity = parser.verbosity
+	parser.output.precision = parser.precision
+	parser.output.autoFlush = parser.autoFlush
+	if parser.debugJSONFn != "" {
+		parser.output.debugJSON = newDebugJSONFile(parser.debugJSONFn)
+		defer parser.output.debugJSON.close()
 	}
-}
-
-func (parser *Parser) writeChart(i int) {
-	if len(parser.output.points) == 0 {
-		return
+	parser.output.AssertTolerance(parser.absTolerance, parser.relTolerance)
+	if parser.casePrefix != "" {
+		parser.output.setPrefix(parser.casePrefix)
 	}
-
-	p, err := plot.New()
-	if err != nil {
-		log.Fatalln("Error creating plot:", err)
+	parser.output.prefixLine = parser.prefixLine
+	parser.output.noPrefix = parser.noPrefix
+	parser.output.raw = parser.raw
+	if parser.caseOutputs {
+		parser.output.caseFn = parser.baseFn + parser.shardSuffix() + ".case"
 	}
+	if parser.yes != "" || parser.no != "" {
+		parser.output.yes, parser.output.no = parser.yes, parser.no
+	}
+	if parser.possible != "" || parser.impossible != "" {
+		parser.output.possible, parser.output.impossible = parser.possible, parser.impossible
+	}
+	parser.input = newInput(scanner)
+	parser.input.fn = parser.inputFn
+	if parser.input.fn == "" {
+		parser.input.fn = parser.baseFn
+	}
+	parser.input.returnErrors = parser.parseErrors
 
-	err = plotutil.AddLinePoints(p, "", parser.output.points)
-	if err != nil {
-		log.Fatalln("Error adding linepoints:", err)
+	T := parser.readCaseCount(parser.input)
+	if err := parser.input.Err(); err != nil {
+		log.Fatalln("Error reading number of cases:", err)
 	}
 
-	err = p.Save(4*vg.Inch, 4*vg.Inch, parser.baseFn+strconv.Itoa(i)+".png")
-	if err != nil {
-		log.Fatalln("Error saving img:", err)
+	if parser.gcPercent != 0 {
+		defer debug.SetGCPercent(debug.SetGCPercent(parser.gcPercent))
 	}
 
-	parser.output.points = parser.output.points[:0]
+	parser.runner = newRunner(parser)
+	defer parser.runner.stop()
+
+	parser.results = nil
+	parser.heapPeak, parser.heapProfiles = 0, 0
+	consumed := true
+	startTime := time.Now().UnixNano()
+	if parser.fileTimeLimit > 0 {
+		parser.fileDeadline = time.Unix(0, startTime).Add(parser.fileTimeLimit)
+	}
+	for i := 1; !parser.isInterrupted() && moreCases(parser.input, i, T); i++ {
+		if !parser.selected(i) {
+			parser.skipTestCase(i)
+			parser.writePrevious(i)
+			continue
+		}
+		parser.dashboard.startCase(parser, i)
+		result := parser.runner.run(i)
+		parser.dashboard.finishCase(parser.inputFn, result)
+		parser.notifier.failure(parser.inputFn, result)
+		if parser.strict {
+			consumed = parser.checkCaseConsumed(result) && consumed
+		}
+		parser.results = append(parser.results, result)
+		parser.printProgress(i, T, time.Unix(0, startTime))
+	}
+	duration := time.Now().UnixNano() - startTime
+	if parser.progress && !parser.quiet && T < 0 {
+		fmt.Fprintln(os.Stderr)
+	}
+	parser.dashboard.finishFile(parser.inputFn)
+	if parser.strict && !parser.isInterrupted() {
+		parser.checkFileConsumed(consumed)
+	}
+	if parser.summary != nil {
+		parser.summary.add(parser.inputFn, parser.results, time.Duration(duration))
+	}
+	if parser.quiet {
+		return
+	}
+	log.Println("Total time:", formatDuration(duration))
+	parser.logVerdicts()
+	if parser.timingChart {
+		parser.writeTimingChart(parser.results)
+	}
 }
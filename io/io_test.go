@@ -0,0 +1,195 @@
+package io
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestFile(t *testing.T, fn, data string) {
+	if err := ioutil.WriteFile(fn, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "codejam")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func double(input *Input, output *Output) {
+	output.Print(2 * input.Int())
+}
+
+func TestParserOptions(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	inputFn := filepath.Join(dir, "A.in")
+	writeTestFile(t, inputFn, "3\n1\n2\n3\n")
+	writeTestFile(t, filepath.Join(dir, "A.correct"), "Case #1: 2\nCase #2: 5\nCase #3: 6\n")
+
+	parser := newParser(double, WithArgs(), WithInputFiles(inputFn), WithNoProfile())
+	parser.Run()
+
+	out, err := ioutil.ReadFile(filepath.Join(dir, "A.out"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Case #1: 2\nCase #2: 4\nCase #3: 6\n", string(out))
+
+	assert.Equal(t, []caseResult{{caseN: 1, verdict: OK}, {caseN: 2, verdict: WA}, {caseN: 3, verdict: OK}}, withoutMeasurements(parser.results))
+	assert.True(t, parser.hasFailures())
+}
+
+func TestParserChecker(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	inputFn := filepath.Join(dir, "A.in")
+	writeTestFile(t, inputFn, "2\n1\n2\n")
+	writeTestFile(t, filepath.Join(dir, "A.correct"), "Case #1: 3\nCase #2: 3\n")
+
+	even := func(input, produced, correct string) bool {
+		return produced == "2" || produced == "4"
+	}
+	parser := newParser(double, WithArgs(inputFn), WithNoProfile(), WithChecker(even))
+	parser.Run()
+
+	assert.Equal(t, []caseResult{{caseN: 1, verdict: OK}, {caseN: 2, verdict: OK}}, withoutMeasurements(parser.results))
+	assert.False(t, parser.hasFailures())
+}
+
+func withoutMeasurements(results []caseResult) []caseResult {
+	stripped := make([]caseResult, len(results))
+	for i, r := range results {
+		stripped[i] = r
+		stripped[i].duration = 0
+		stripped[i].memory = 0
+		stripped[i].tokens = 0
+	}
+	return stripped
+}
+
+func TestParserCheckerWithoutCorrect(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	inputFn := filepath.Join(dir, "A.in")
+	writeTestFile(t, inputFn, "2\n3\n2\n")
+
+	var inputs []string
+	valid := func(input, produced, correct string) bool {
+		inputs = append(inputs, input)
+		return correct == "" && produced == "4"
+	}
+	parser := newParser(double, WithArgs(inputFn), WithNoProfile(), WithChecker(valid))
+	parser.Run()
+
+	assert.Equal(t, []string{"3", "2"}, inputs)
+	assert.Equal(t, []caseResult{{caseN: 1, verdict: WA}, {caseN: 2, verdict: OK}}, withoutMeasurements(parser.results))
+}
+
+func TestFindCorrectFn(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	inputFn := filepath.Join(dir, "A.in")
+	parser := newParser(double)
+	parser.SetFn(inputFn)
+	assert.Equal(t, filepath.Join(dir, "A.correct"), parser.correctFn)
+
+	writeTestFile(t, filepath.Join(dir, "A.out.ok"), "")
+	writeTestFile(t, filepath.Join(dir, "A.expected"), "")
+	parser.SetFn(inputFn)
+	assert.Equal(t, filepath.Join(dir, "A.expected"), parser.correctFn)
+
+	WithCorrectExtensions(".ans", ".out.ok")(parser)
+	parser.SetFn(inputFn)
+	assert.Equal(t, filepath.Join(dir, "A.out.ok"), parser.correctFn)
+}
+
+func TestCaseDeadline(t *testing.T) {
+	start := time.Unix(100, 0)
+	parser := newParser(double)
+	assert.True(t, parser.caseDeadline(start).IsZero())
+
+	parser.timeLimit = time.Second
+	assert.Equal(t, time.Unix(101, 0), parser.caseDeadline(start))
+
+	parser.fileDeadline = time.Unix(100, 500)
+	assert.Equal(t, time.Unix(100, 500), parser.caseDeadline(start))
+
+	parser.timeLimit = 0
+	assert.Equal(t, time.Unix(100, 500), parser.caseDeadline(start))
+
+	input := initInput("")
+	assert.True(t, input.TimeLeft() > time.Hour)
+	input.deadline = time.Now().Add(time.Minute)
+	assert.True(t, input.TimeLeft() <= time.Minute)
+}
+
+func TestTeeWriter(t *testing.T) {
+	parser := newParser(double)
+	assert.Nil(t, parser.teeWriter())
+
+	WithTee("stderr")(parser)
+	assert.Equal(t, os.Stderr, parser.teeWriter())
+}
+
+func TestAtomicOutput(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	outputFn := filepath.Join(dir, "A.out")
+	writeTestFile(t, outputFn, "Case #1: old\n")
+	f, err := createAtomic(outputFn)
+	assert.NoError(t, err)
+	f.Write([]byte("Case #1: new\n"))
+
+	out, err := ioutil.ReadFile(outputFn)
+	assert.NoError(t, err)
+	assert.Equal(t, "Case #1: old\n", string(out))
+
+	assert.NoError(t, f.commit())
+	out, err = ioutil.ReadFile(outputFn)
+	assert.NoError(t, err)
+	assert.Equal(t, "Case #1: new\n", string(out))
+	_, err = os.Stat(outputFn + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRun(t *testing.T) {
+	b := &bytes.Buffer{}
+	Run(double, strings.NewReader("2\n1\n2\n"), b)
+	assert.Equal(t, "Case #1: 2\nCase #2: 4\n", b.String())
+
+	b.Reset()
+	Run(double, strings.NewReader("3\n"), b, WithInputFormat(FormatSingle), WithNoCasePrefix())
+	assert.Equal(t, "6\n", b.String())
+}
+
+func TestCaseOutputs(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	inputFn := filepath.Join(dir, "A.in")
+	writeTestFile(t, inputFn, "2\n1\n2\n")
+	parser := newParser(double, WithArgs(inputFn), WithNoProfile(), WithCaseOutputs())
+	parser.quiet = true
+	parser.Run()
+
+	out, err := ioutil.ReadFile(filepath.Join(dir, "A.out"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Case #1: 2\nCase #2: 4\n", string(out))
+	out, err = ioutil.ReadFile(filepath.Join(dir, "A.case2.out"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Case #2: 4\n", string(out))
+}
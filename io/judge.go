@@ -0,0 +1,180 @@
+package io
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+const transcriptTail = 20
+
+type transcript struct {
+	sync.Mutex
+	buffer bytes.Buffer
+	f      *os.File
+}
+
+func openTranscript(fn string) *transcript {
+	t := &transcript{}
+	if fn == "" {
+		return t
+	}
+	f, err := os.Create(fn)
+	if err != nil {
+		log.Fatalln("Error creating transcript:", err)
+	}
+	t.f = f
+	return t
+}
+
+func (t *transcript) close() {
+	t.Lock()
+	defer t.Unlock()
+	if t.f != nil {
+		t.f.Close()
+		t.f = nil
+	}
+}
+
+func (t *transcript) tail(n int) string {
+	t.Lock()
+	defer t.Unlock()
+	lines := strings.SplitAfter(strings.TrimSuffix(t.buffer.String(), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "") + "\n"
+}
+
+type transcriptWriter struct {
+	t         *transcript
+	prefix    string
+	lineStart bool
+}
+
+func newTranscriptWriter(t *transcript, prefix string) *transcriptWriter {
+	return &transcriptWriter{
+		t:         t,
+		prefix:    prefix,
+		lineStart: true,
+	}
+}
+
+func (tw *transcriptWriter) Write(data []byte) (int, error) {
+	tw.t.Lock()
+	defer tw.t.Unlock()
+	start := tw.t.buffer.Len()
+	for _, b := range data {
+		if tw.lineStart {
+			tw.t.buffer.WriteString(tw.prefix)
+		}
+		tw.t.buffer.WriteByte(b)
+		tw.lineStart = b == '\n'
+	}
+	if tw.t.f != nil {
+		if _, err := tw.t.f.Write(tw.t.buffer.Bytes()[start:]); err != nil {
+			log.Println("Error writing transcript:", err)
+			tw.t.f.Close()
+			tw.t.f = nil
+		}
+	}
+	return len(data), nil
+}
+
+type judgeReader struct {
+	r     io.Reader
+	onEOF func()
+}
+
+func (jr *judgeReader) Read(data []byte) (int, error) {
+	n, err := jr.r.Read(data)
+	if err == io.EOF {
+		jr.onEOF()
+	}
+	return n, err
+}
+
+type judge struct {
+	cmd        *exec.Cmd
+	stdin      io.WriteCloser
+	stdout     io.Reader
+	transcript *transcript
+	once       sync.Once
+}
+
+func newJudge(command, transcriptFn string) *judge {
+	args, err := splitCommand(command)
+	if err != nil {
+		log.Fatalln("Invalid judge command:", err)
+	}
+	if len(args) == 0 {
+		log.Fatalln("Empty judge command")
+	}
+
+	j := &judge{
+		cmd:        exec.Command(args[0], args[1:]...),
+		transcript: openTranscript(transcriptFn),
+	}
+	j.cmd.Stderr = os.Stderr
+
+	stdin, err := j.cmd.StdinPipe()
+	if err != nil {
+		log.Fatalln("Error creating judge stdin:", err)
+	}
+	stdout, err := j.cmd.StdoutPipe()
+	if err != nil {
+		log.Fatalln("Error creating judge stdout:", err)
+	}
+	j.stdin = stdin
+	j.stdout = stdout
+
+	return j
+}
+
+func (j *judge) reader() io.Reader {
+	return &judgeReader{
+		r:     io.TeeReader(j.stdout, newTranscriptWriter(j.transcript, judgePrefix)),
+		onEOF: j.finish,
+	}
+}
+
+func (j *judge) writer() io.Writer {
+	return io.MultiWriter(j.stdin, newTranscriptWriter(j.transcript, solutionPrefix))
+}
+
+func (j *judge) finish() {
+	j.once.Do(func() {
+		j.stdin.Close()
+		err := j.cmd.Wait()
+		j.transcript.close()
+
+		if err != nil {
+			log.Print("Judge transcript tail:\n", j.transcript.tail(transcriptTail))
+			log.Fatalln("Judge verdict: failed:", err)
+		}
+		log.Println("Judge verdict: accepted")
+	})
+}
+
+func (parser *Parser) ParseJudge(command string) {
+	parser.interactive = true
+	parser.baseFn = "judge"
+	parser.profileFn = parser.baseFn + ".prof"
+	parser.compareOutput = nil
+
+	transcriptFn := parser.baseFn + ".transcript"
+	if parser.recordFn != "" {
+		transcriptFn = parser.recordFn
+	}
+	j := newJudge(command, transcriptFn)
+	if err := j.cmd.Start(); err != nil {
+		log.Fatalln("Error starting judge:", err)
+	}
+
+	parser.parse(j.reader(), j.writer())
+	j.finish()
+}
@@ -0,0 +1,54 @@
+package io
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranscript(t *testing.T) {
+	tr := &transcript{}
+	judgeW := newTranscriptWriter(tr, "judge: ")
+	solutionW := newTranscriptWriter(tr, "solution: ")
+
+	judgeW.Write([]byte("2\n"))
+	solutionW.Write([]byte("1 "))
+	solutionW.Write([]byte("2\n"))
+	judgeW.Write([]byte("-1\n"))
+
+	assert.Equal(t, "judge: 2\nsolution: 1 2\njudge: -1\n", tr.buffer.String())
+	assert.Equal(t, "solution: 1 2\njudge: -1\n", tr.tail(2))
+}
+
+func TestTranscriptStreamed(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+	fn := filepath.Join(dir, "judge.transcript")
+
+	tr := openTranscript(fn)
+	newTranscriptWriter(tr, "judge: ").Write([]byte("2\n"))
+
+	data, err := ioutil.ReadFile(fn)
+	assert.NoError(t, err)
+	assert.Equal(t, "judge: 2\n", string(data))
+	tr.close()
+}
+
+func TestReplay(t *testing.T) {
+	judge, solution := parseTranscript([]byte("judge: 2\nsolution: 1 2\njudge: -1\nsolution: 3\n"))
+	assert.Equal(t, "2\n-1\n", string(judge))
+	assert.Equal(t, []string{"1 2", "3"}, solution)
+
+	rw := &replayWriter{expected: solution}
+	rw.Write([]byte("1 "))
+	rw.Write([]byte("2\n3"))
+	assert.False(t, rw.diverged)
+	assert.Equal(t, 1, rw.line)
+
+	rw.Write([]byte("\n4\n"))
+	assert.True(t, rw.diverged)
+	assert.Equal(t, 3, rw.line)
+}
@@ -0,0 +1,68 @@
+package io
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type Normalizer func(string) string
+
+func TrimTrailingSpace(data string) string {
+	lines := strings.Split(data, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " \t\r")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func Lowercase(data string) string {
+	return strings.ToLower(data)
+}
+
+func CollapseSpaces(data string) string {
+	lines := strings.Split(data, "\n")
+	for i := range lines {
+		lines[i] = strings.Join(strings.Fields(lines[i]), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func SortTokens(data string) string {
+	tokens := strings.Fields(data)
+	sort.Strings(tokens)
+	return strings.Join(tokens, " ")
+}
+
+var normalizers = map[string]Normalizer{
+	"trim":   TrimTrailingSpace,
+	"lower":  Lowercase,
+	"spaces": CollapseSpaces,
+	"sort":   SortTokens,
+}
+
+func (parser *Parser) normalize(data string) string {
+	for _, normalizer := range parser.normalizers {
+		data = normalizer(data)
+	}
+	return data
+}
+
+type normalizersValue struct {
+	parser *Parser
+}
+
+func (nv normalizersValue) String() string {
+	return ""
+}
+
+func (nv normalizersValue) Set(str string) error {
+	for _, name := range strings.Split(str, ",") {
+		normalizer, ok := normalizers[strings.TrimSpace(name)]
+		if !ok {
+			return fmt.Errorf("unknown normalizer %q, use trim, lower, spaces or sort", name)
+		}
+		nv.parser.normalizers = append(nv.parser.normalizers, normalizer)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package io
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizers(t *testing.T) {
+	assert.Equal(t, "a b\nc", TrimTrailingSpace("a b  \nc\t"))
+	assert.Equal(t, "yes", Lowercase("YeS"))
+	assert.Equal(t, "a b\nc d", CollapseSpaces(" a   b\nc \t d "))
+	assert.Equal(t, "1 2 3", SortTokens("3 1\n2"))
+}
+
+func TestParserNormalize(t *testing.T) {
+	parser := newParser(double)
+	assert.False(t, parser.equal("", "POSSIBLE", "possible"))
+
+	assert.NoError(t, normalizersValue{parser}.Set("lower,spaces"))
+	assert.True(t, parser.equal("", "POSSIBLE  1", "possible 1"))
+	assert.Error(t, normalizersValue{parser}.Set("unknown"))
+
+	parser = newParser(double, WithNormalizer(SortTokens))
+	assert.True(t, parser.equal("", "3 1 2", "1 2 3"))
+}
@@ -0,0 +1,46 @@
+package io
+
+import (
+	"fmt"
+	"log"
+	"sync"
+)
+
+type notifier struct {
+	target       string
+	firstFailure sync.Once
+}
+
+func newNotifier(target string) *notifier {
+	if target == "" {
+		return nil
+	}
+	if target != "desktop" && !isURL(target) {
+		log.Fatalf("Unknown notify target %q, use desktop or a webhook url\n", target)
+	}
+	return &notifier{target: target}
+}
+
+func (n *notifier) failure(inputFn string, result caseResult) {
+	if n == nil || !result.verdict.failed() {
+		return
+	}
+	n.firstFailure.Do(func() {
+		n.send(fmt.Sprintf("%s case #%d: %s", inputFn, result.caseN, result.verdict))
+	})
+}
+
+func (parser *Parser) notifyFinished() {
+	if parser.notifier == nil || parser.summary == nil {
+		return
+	}
+	parser.summary.Lock()
+	results := parser.summary.allResults()
+	parser.summary.Unlock()
+
+	message := fmt.Sprintf("Run finished: %d cases", len(results))
+	if verdicts := formatVerdicts(results); verdicts != "" {
+		message += ", " + verdicts
+	}
+	parser.notifier.send(message)
+}
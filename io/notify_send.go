@@ -0,0 +1,55 @@
+//go:build !submit
+// +build !submit
+
+package io
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+var webhookClient = &http.Client{Timeout: 5 * time.Second}
+
+func (n *notifier) send(message string) {
+	if n == nil {
+		return
+	}
+	var err error
+	if n.target == "desktop" {
+		err = desktopNotification(message)
+	} else {
+		err = webhookNotification(n.target, message)
+	}
+	if err != nil {
+		log.Println("Error sending notification:", err)
+	}
+}
+
+func desktopNotification(message string) error {
+	if runtime.GOOS == "darwin" {
+		return exec.Command("osascript", "-e", fmt.Sprintf("display notification %q with title \"codejam\"", message)).Run()
+	}
+	return exec.Command("notify-send", "codejam", message).Run()
+}
+
+func webhookNotification(url, message string) error {
+	body, err := json.Marshal(map[string]string{"text": message})
+	if err != nil {
+		return err
+	}
+	resp, err := webhookClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+//go:build !submit
+// +build !submit
+
+package io
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifyWebhookTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	timeout := webhookClient.Timeout
+	webhookClient.Timeout = 50 * time.Millisecond
+	defer func() {
+		webhookClient.Timeout = timeout
+	}()
+
+	start := time.Now()
+	assert.Error(t, webhookNotification(server.URL, "message"))
+	assert.True(t, time.Since(start) < time.Second)
+}
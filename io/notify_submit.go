@@ -0,0 +1,7 @@
+//go:build submit
+// +build submit
+
+package io
+
+func (n *notifier) send(message string) {
+}
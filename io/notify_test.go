@@ -0,0 +1,41 @@
+package io
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifyWebhook(t *testing.T) {
+	messages := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]string{}
+		json.NewDecoder(r.Body).Decode(&body)
+		messages = append(messages, body["text"])
+	}))
+	defer server.Close()
+
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+	inputFn := filepath.Join(dir, "A.in")
+	writeTestFile(t, inputFn, "3\n1\n2\n3\n")
+	writeTestFile(t, filepath.Join(dir, "A.correct"), "Case #1: 2\nCase #2: 5\nCase #3: 7\n")
+
+	parser := newParser(double, WithArgs(inputFn), WithNoProfile(), WithNotify(server.URL))
+	parser.quiet = true
+	parser.Run()
+
+	assert.Equal(t, []string{inputFn + " case #2: WA", "Run finished: 3 cases, 1 OK, 2 WA"}, messages)
+}
+
+func TestNotifierNil(t *testing.T) {
+	var n *notifier
+	n.send("message")
+	n.failure("A.in", caseResult{verdict: WA})
+	assert.Nil(t, newNotifier(""))
+}
@@ -0,0 +1,332 @@
+package io
+
+import "time"
+
+type Option func(*Parser)
+
+func WithArgs(args ...string) Option {
+	return func(parser *Parser) {
+		parser.args = args
+	}
+}
+
+func WithConfigFile(fn string) Option {
+	return func(parser *Parser) {
+		parser.configFn = fn
+	}
+}
+
+func WithInputFiles(inputFns ...string) Option {
+	return func(parser *Parser) {
+		parser.inputFns = inputFns
+	}
+}
+
+func WithVerbosity(level int) Option {
+	return func(parser *Parser) {
+		parser.verbosity = level
+	}
+}
+
+func WithPeriod(d time.Duration) Option {
+	return func(parser *Parser) {
+		parser.period = d
+	}
+}
+
+func WithNoProfile() Option {
+	return func(parser *Parser) {
+		parser.noProfile = true
+	}
+}
+
+func WithProfileTimes(start, stop time.Duration) Option {
+	return func(parser *Parser) {
+		parser.profileStart = start
+		parser.profileStop = stop
+	}
+}
+
+func WithMemProfile() Option {
+	return func(parser *Parser) {
+		parser.memProfile = true
+	}
+}
+
+func WithMemThreshold(mb int) Option {
+	return func(parser *Parser) {
+		parser.memThreshold = mb
+	}
+}
+
+func WithGCPercent(percent int) Option {
+	return func(parser *Parser) {
+		parser.gcPercent = percent
+	}
+}
+
+func WithGCBetweenCases() Option {
+	return func(parser *Parser) {
+		parser.gcBetween = true
+	}
+}
+
+func WithSandbox(cpuLimit time.Duration, memLimitMB int) Option {
+	return func(parser *Parser) {
+		parser.sandbox = true
+		parser.cpuLimit = cpuLimit
+		parser.memLimit = memLimitMB
+	}
+}
+
+func WithRecord(transcriptFn string) Option {
+	return func(parser *Parser) {
+		parser.recordFn = transcriptFn
+	}
+}
+
+func WithReplay(transcriptFn string) Option {
+	return func(parser *Parser) {
+		parser.replayFn = transcriptFn
+	}
+}
+
+func WithParallelism(n int) Option {
+	return func(parser *Parser) {
+		parser.parallelism = n
+	}
+}
+
+func WithChecker(checker Checker) Option {
+	return func(parser *Parser) {
+		parser.checker = checker
+	}
+}
+
+func WithTimeLimit(d time.Duration) Option {
+	return func(parser *Parser) {
+		parser.timeLimit = d
+	}
+}
+
+func WithFileTimeLimit(d time.Duration) Option {
+	return func(parser *Parser) {
+		parser.fileTimeLimit = d
+	}
+}
+
+func WithSkipTLE() Option {
+	return func(parser *Parser) {
+		parser.skipTLE = true
+	}
+}
+
+func WithGenerator(generator GeneratorFunc) Option {
+	return func(parser *Parser) {
+		parser.generator = generator
+	}
+}
+
+func WithBrute(brute TestCaseFunc) Option {
+	return func(parser *Parser) {
+		parser.brute = brute
+	}
+}
+
+func WithReference(command string) Option {
+	return func(parser *Parser) {
+		parser.referenceCmd = command
+	}
+}
+
+func WithCasePrefix(prefix string, ownLine bool) Option {
+	return func(parser *Parser) {
+		parser.casePrefix = prefix
+		parser.prefixLine = ownLine
+	}
+}
+
+func WithNoCasePrefix() Option {
+	return func(parser *Parser) {
+		parser.noPrefix = true
+	}
+}
+
+func WithTee(target string) Option {
+	return func(parser *Parser) {
+		parser.tee = target
+	}
+}
+
+func WithAutoFlush() Option {
+	return func(parser *Parser) {
+		parser.autoFlush = true
+	}
+}
+
+func WithCaseOutputs() Option {
+	return func(parser *Parser) {
+		parser.caseOutputs = true
+	}
+}
+
+func WithRawOutput() Option {
+	return func(parser *Parser) {
+		parser.raw = true
+	}
+}
+
+func WithChartSize(widthInches, heightInches float64) Option {
+	return func(parser *Parser) {
+		parser.chartWidth = widthInches
+		parser.chartHeight = heightInches
+	}
+}
+
+func WithChartFormat(format string) Option {
+	return func(parser *Parser) {
+		parser.chartFormat = format
+	}
+}
+
+func WithPointsCSV() Option {
+	return func(parser *Parser) {
+		parser.pointsCSV = true
+	}
+}
+
+func WithProgress() Option {
+	return func(parser *Parser) {
+		parser.progress = true
+	}
+}
+
+func WithTimingChart() Option {
+	return func(parser *Parser) {
+		parser.timingChart = true
+	}
+}
+
+func WithFloatPrecision(digits int) Option {
+	return func(parser *Parser) {
+		parser.precision = digits
+	}
+}
+
+func WithYesNo(yes, no string) Option {
+	return func(parser *Parser) {
+		parser.yes = yes
+		parser.no = no
+	}
+}
+
+func WithPossibleImpossible(possible, impossible string) Option {
+	return func(parser *Parser) {
+		parser.possible = possible
+		parser.impossible = impossible
+	}
+}
+
+func WithFloatTolerance(abs, rel float64) Option {
+	return func(parser *Parser) {
+		parser.absTolerance = abs
+		parser.relTolerance = rel
+	}
+}
+
+func WithAnyLineOrder() Option {
+	return func(parser *Parser) {
+		parser.anyLineOrder = true
+	}
+}
+
+func WithReport(reportFn string) Option {
+	return func(parser *Parser) {
+		parser.reportFn = reportFn
+	}
+}
+
+func WithNotify(target string) Option {
+	return func(parser *Parser) {
+		parser.notify = target
+	}
+}
+
+func WithDashboard(addr string) Option {
+	return func(parser *Parser) {
+		parser.httpAddr = addr
+	}
+}
+
+func WithArchive(dir string) Option {
+	return func(parser *Parser) {
+		parser.archiveDir = dir
+	}
+}
+
+func WithNormalizer(normalizers ...Normalizer) Option {
+	return func(parser *Parser) {
+		parser.normalizers = append(parser.normalizers, normalizers...)
+	}
+}
+
+func WithCorrectExtensions(exts ...string) Option {
+	return func(parser *Parser) {
+		parser.correctExts = exts
+	}
+}
+
+func WithDeterminism() Option {
+	return func(parser *Parser) {
+		parser.determinism = true
+	}
+}
+
+func WithValidator(validator ValidateFunc) Option {
+	return func(parser *Parser) {
+		parser.validator = validator
+	}
+}
+
+func WithInputFormat(format string) Option {
+	return func(parser *Parser) {
+		parser.format = format
+	}
+}
+
+func WithDelimiters(delims string) Option {
+	return func(parser *Parser) {
+		parser.delimiters = delims
+	}
+}
+
+func WithStrictInput() Option {
+	return func(parser *Parser) {
+		parser.strict = true
+	}
+}
+
+func WithBufferSize(bytes int) Option {
+	return func(parser *Parser) {
+		parser.bufferSize = bytes
+	}
+}
+
+func WithParseErrors() Option {
+	return func(parser *Parser) {
+		parser.parseErrors = true
+	}
+}
+
+func WithSeed(seed int64) Option {
+	return func(parser *Parser) {
+		parser.seed = seed
+	}
+}
+
+func WithShard(index, count int) Option {
+	return func(parser *Parser) {
+		parser.shardIndex = index
+		parser.shardCount = count
+	}
+}
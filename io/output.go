@@ -3,12 +3,20 @@ package io
 import (
 	"bytes"
 	"fmt"
+	"image/color"
 	"io"
+	"io/ioutil"
 	"log"
+	"math/big"
+	"reflect"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 	"unicode"
 
-	"github.com/gonum/plot/plotter"
+	"github.com/matematik7/codejam-go/graph"
 )
 
 type Output struct {
@@ -16,15 +24,67 @@ type Output struct {
 
 	caseN int
 	input *Input
+	start time.Time
 
-	output *bytes.Buffer
+	output     *bytes.Buffer
+	caseBuffer bytes.Buffer
+	scratch    [64]byte
+	caseFn     string
+
+	interactive bool
+	quiet       bool
+	verbosity   int
+	abandoned   int32
+
+	yes        string
+	no         string
+	possible   string
+	impossible string
+	precision  int
+
+	prefixBefore string
+	prefixAfter  string
+	prefixNumber bool
+	prefixLine   bool
+	noPrefix     bool
+	raw          bool
+
+	multiline bool
+	lines     int
+	autoFlush bool
+
+	absTolerance float64
+	relTolerance float64
+	ignoreCase   bool
+
+	best proposal
+
+	debugJSON   *debugJSONFile
+	debugValues map[string]interface{}
 
 	periodicPrint       chan struct{}
+	everyPeriod         func()
 	previousPeriodicInt int
 	periodicCount       int
 	prevPeriodicCount   int
-
-	points plotter.XYs
+	sparkline           []float64
+
+	points  chartXYs
+	series  []chartSeries
+	scatter chartXYs
+	hist    []float64
+	bins    int
+	heatmap [][]float64
+	grid    [][]byte
+	palette map[byte]color.Color
+	graph   []byte
+	frames  [][][]byte
+
+	chartTitle string
+	xLabel     string
+	yLabel     string
+	logX       bool
+	logY       bool
 }
 
 func newOutput(w io.Writer) *Output {
@@ -32,6 +92,25 @@ func newOutput(w io.Writer) *Output {
 		w:             w,
 		output:        &bytes.Buffer{},
 		periodicPrint: make(chan struct{}, 10),
+		yes:           "YES",
+		no:            "NO",
+		possible:      "POSSIBLE",
+		impossible:    "IMPOSSIBLE",
+		precision:     -1,
+		verbosity:     1,
+		prefixBefore:  "Case #",
+		prefixAfter:   ":",
+		prefixNumber:  true,
+	}
+}
+
+func (o *Output) setPrefix(prefix string) {
+	parts := strings.SplitN(prefix, "%d", 2)
+	o.prefixBefore = parts[0]
+	o.prefixAfter = ""
+	o.prefixNumber = len(parts) == 2
+	if o.prefixNumber {
+		o.prefixAfter = parts[1]
 	}
 }
 
@@ -46,79 +125,347 @@ func (o *Output) resetPeriodic() {
 }
 
 func (o *Output) triggerPeriodic() {
+	if o.quiet {
+		return
+	}
 	o.resetPeriodic()
 	o.periodicPrint <- struct{}{}
 }
 
+func isString(a interface{}) bool {
+	switch a.(type) {
+	case string:
+		return true
+	case nil, int, int64, bool, float64, []byte, *big.Int:
+		return false
+	}
+	return reflect.TypeOf(a).Kind() == reflect.String
+}
+
+func (o *Output) write(a interface{}) {
+	switch v := a.(type) {
+	case string:
+		o.output.WriteString(v)
+	case int:
+		o.output.Write(strconv.AppendInt(o.scratch[:0], int64(v), 10))
+	case int64:
+		o.output.Write(strconv.AppendInt(o.scratch[:0], v, 10))
+	case bool:
+		o.output.Write(strconv.AppendBool(o.scratch[:0], v))
+	case float64:
+		if o.precision >= 0 {
+			o.output.Write(appendFloat(o.scratch[:0], v, o.precision))
+			return
+		}
+		o.output.Write(strconv.AppendFloat(o.scratch[:0], v, 'g', -1, 64))
+	case *big.Int:
+		if v == nil {
+			o.output.WriteString("<nil>")
+			return
+		}
+		o.output.Write(v.Append(o.scratch[:0], 10))
+	default:
+		fmt.Fprint(o.output, a)
+	}
+}
+
+func appendFloat(dst []byte, f float64, digits int) []byte {
+	dst = strconv.AppendFloat(dst, f, 'f', digits, 64)
+	if dst[0] == '-' && len(bytes.Trim(dst[1:], "0.")) == 0 {
+		dst = append(dst[:0], dst[1:]...)
+	}
+	return dst
+}
+
 func (o *Output) Print(a ...interface{}) {
-	fmt.Fprint(o.output, a...)
+	for i, arg := range a {
+		if i > 0 && !isString(arg) && !isString(a[i-1]) {
+			o.output.WriteByte(' ')
+		}
+		o.write(arg)
+	}
+	o.writeThrough()
 }
 
 func (o *Output) Println(a ...interface{}) {
-	fmt.Fprintln(o.output, a...)
+	for i, arg := range a {
+		if i > 0 {
+			o.output.WriteByte(' ')
+		}
+		o.write(arg)
+	}
+	o.output.WriteByte('\n')
+	o.writeThrough()
+}
+
+func (o *Output) PrintInt(a int) {
+	o.output.Write(strconv.AppendInt(o.scratch[:0], int64(a), 10))
+	o.writeThrough()
+}
+
+func (o *Output) Float(f float64, digits int) {
+	o.output.Write(appendFloat(o.scratch[:0], f, digits))
+	o.writeThrough()
+}
+
+func (o *Output) Mod(x, m int) {
+	if m <= 0 {
+		o.Fatalf("Mod with non-positive modulus %d\n", m)
+	}
+	x %= m
+	if x < 0 {
+		x += m
+	}
+	o.PrintInt(x)
+}
+
+func (o *Output) Mod1e9_7(x int) {
+	o.Mod(x, 1000000007)
+}
+
+func (o *Output) PrintBigInt(a *big.Int) {
+	o.write(a)
+	o.writeThrough()
+}
+
+func (o *Output) PrintInts(a []int) {
+	for i, n := range a {
+		if i > 0 {
+			o.output.WriteByte(' ')
+		}
+		o.output.Write(strconv.AppendInt(o.scratch[:0], int64(n), 10))
+	}
+	o.writeThrough()
+}
+
+func (o *Output) Multiline(lines int) {
+	o.multiline = true
+	o.lines = lines
+}
+
+func (o *Output) Lines(lines ...interface{}) {
+	o.multiline = true
+	for _, line := range lines {
+		if o.output.Len() > 0 && o.output.Bytes()[o.output.Len()-1] != '\n' {
+			o.output.WriteByte('\n')
+		}
+		o.write(line)
+		o.output.WriteByte('\n')
+	}
+	o.writeThrough()
+}
+
+func (o *Output) YesNo(yes bool) {
+	if yes {
+		o.output.WriteString(o.yes)
+	} else {
+		o.output.WriteString(o.no)
+	}
+	o.writeThrough()
+}
+
+func (o *Output) PossibleImpossible(possible bool) {
+	if possible {
+		o.output.WriteString(o.possible)
+	} else {
+		o.output.WriteString(o.impossible)
+	}
+	o.writeThrough()
+}
+
+func (o *Output) Raw(a ...interface{}) {
+	if o.isAbandoned() {
+		runtime.Goexit()
+	}
+	fmt.Fprint(o.w, a...)
+}
+
+func (o *Output) Ints(a []int) {
+	o.PrintInts(a)
+}
+
+func (o *Output) Join(sep string, a ...interface{}) {
+	for i, arg := range a {
+		if i > 0 {
+			o.output.WriteString(sep)
+		}
+		o.write(arg)
+	}
+	o.writeThrough()
 }
 
 func (o *Output) Printf(format string, a ...interface{}) {
 	fmt.Fprintf(o.output, format, a...)
+	o.writeThrough()
+}
+
+func (o *Output) abandon() *Output {
+	if !atomic.CompareAndSwapInt32(&o.abandoned, 0, 1) {
+		return nil
+	}
+	newO := newOutput(o.w)
+	newO.interactive = o.interactive
+	newO.quiet = o.quiet
+	newO.verbosity = o.verbosity
+	newO.yes, newO.no = o.yes, o.no
+	newO.possible, newO.impossible = o.possible, o.impossible
+	newO.precision = o.precision
+	newO.autoFlush = o.autoFlush
+	newO.debugJSON = o.debugJSON
+	newO.absTolerance, newO.relTolerance, newO.ignoreCase = o.absTolerance, o.relTolerance, o.ignoreCase
+	newO.prefixBefore, newO.prefixAfter = o.prefixBefore, o.prefixAfter
+	newO.prefixNumber, newO.prefixLine, newO.noPrefix = o.prefixNumber, o.prefixLine, o.noPrefix
+	newO.raw = o.raw
+	newO.caseFn = o.caseFn
+	return newO
+}
+
+func (o *Output) isAbandoned() bool {
+	return atomic.LoadInt32(&o.abandoned) == 1
+}
+
+func (o *Output) begin() {
+	atomic.StoreInt32(&o.abandoned, 0)
+}
+
+func (o *Output) finish() bool {
+	return atomic.CompareAndSwapInt32(&o.abandoned, 0, 2)
+}
+
+func (o *Output) writeThrough() {
+	if !o.interactive {
+		return
+	}
+	if o.isAbandoned() {
+		runtime.Goexit()
+	}
+	o.w.Write(o.output.Bytes())
+	o.output.Reset()
+}
+
+func (o *Output) Flush() {
+	o.writeThrough()
+	if f, ok := o.w.(interface {
+		Flush() error
+	}); ok {
+		if err := f.Flush(); err != nil {
+			log.Fatalln("Error flushing output:", err)
+		}
+	}
 }
 
 func (o *Output) Fatal(a ...interface{}) {
 	o.DebugCase()
-	log.Fatalln(a...)
+	log.Fatal(o.debugPrefix() + fmt.Sprintln(a...))
 }
 
 func (o *Output) Fatalf(format string, a ...interface{}) {
 	o.DebugCase()
-	log.Fatalf(format, a...)
+	log.Fatal(o.debugPrefix() + fmt.Sprintf(format, a...))
 }
 
-func (o *Output) Debug(a ...interface{}) {
+func (o *Output) debugLevel(level int, a ...interface{}) {
+	if o.quiet || o.verbosity < level {
+		return
+	}
 	o.DebugCase()
-	log.Println(a...)
+	log.Print(o.debugPrefix() + fmt.Sprintln(a...))
 }
 
-func (o *Output) Debugf(format string, a ...interface{}) {
+func (o *Output) debugLevelf(level int, format string, a ...interface{}) {
+	if o.quiet || o.verbosity < level {
+		return
+	}
 	o.DebugCase()
-	log.Printf(format, a...)
+	log.Print(o.debugPrefix() + fmt.Sprintf(format, a...))
+}
+
+func (o *Output) Debug(a ...interface{}) {
+	o.debugLevel(1, a...)
+}
+
+func (o *Output) Debugf(format string, a ...interface{}) {
+	o.debugLevelf(1, format, a...)
+}
+
+func (o *Output) Debugv(a ...interface{}) {
+	o.debugLevel(2, a...)
+}
+
+func (o *Output) Debugvf(format string, a ...interface{}) {
+	o.debugLevelf(2, format, a...)
+}
+
+func (o *Output) Trace(a ...interface{}) {
+	o.debugLevel(3, a...)
+}
+
+func (o *Output) Tracef(format string, a ...interface{}) {
+	o.debugLevelf(3, format, a...)
 }
 
 func (o *Output) DebugCase() {
-	log.Printf("Case #%d, input: %v, output: %q\n", o.caseN, o.input.currentCase(), string(o.output.Bytes()))
+	if o.quiet {
+		return
+	}
+	log.Printf("%sinput: %v, output: %q\n", o.debugPrefix(), o.input.currentCase(), string(o.output.Bytes()))
 }
 
-func (o *Output) Periodic(a ...interface{}) {
+func (o *Output) period() bool {
 	select {
 	case <-o.periodicPrint:
-		log.Println(a...)
+		if o.everyPeriod != nil {
+			o.everyPeriod()
+		}
+		return true
 	default:
+		return false
+	}
+}
+
+func (o *Output) EveryPeriod(f func()) {
+	o.everyPeriod = f
+}
+
+func (o *Output) Tick() {
+	o.period()
+}
+
+func (o *Output) Periodic(a ...interface{}) {
+	if o.period() {
+		log.Println(a...)
 	}
 }
 
 func (o *Output) PeriodicInt(a int) {
-	select {
-	case <-o.periodicPrint:
+	if o.period() {
 		log.Println(a, "Rate =", a-o.previousPeriodicInt)
 		o.previousPeriodicInt = a
-	default:
 	}
 }
 
 func (o *Output) PeriodicCount() {
 	o.periodicCount++
-	select {
-	case <-o.periodicPrint:
+	if o.period() {
 		c := o.periodicCount
 		log.Println(c, "Rate =", c-o.prevPeriodicCount)
 		o.prevPeriodicCount = c
-	default:
+	}
+}
+
+func (o *Output) PeriodicSparkline(x float64) {
+	if o.period() {
+		o.sparkline = append(o.sparkline, x)
+		if len(o.sparkline) > sparklineWidth {
+			o.sparkline = o.sparkline[len(o.sparkline)-sparklineWidth:]
+		}
+		log.Println(sparkline(o.sparkline), x)
 	}
 }
 
 func (o *Output) Periodicf(format string, a ...interface{}) {
-	select {
-	case <-o.periodicPrint:
+	if o.period() {
 		log.Printf(format, a...)
-	default:
 	}
 }
 
@@ -133,24 +480,165 @@ func (o *Output) PointInt(x, y int) {
 	o.Point(float64(x), float64(y))
 }
 
+func (o *Output) Plot(series string, x, y float64) {
+	point := struct{ X, Y float64 }{
+		X: x,
+		Y: y,
+	}
+	for j := range o.series {
+		if o.series[j].name == series {
+			o.series[j].points = append(o.series[j].points, point)
+			return
+		}
+	}
+	o.series = append(o.series, chartSeries{name: series, points: chartXYs{point}})
+}
+
+func (o *Output) ChartTitle(title string) {
+	o.chartTitle = title
+}
+
+func (o *Output) ChartLabels(x, y string) {
+	o.xLabel = x
+	o.yLabel = y
+}
+
+func (o *Output) ChartLogScale(x, y bool) {
+	o.logX = x
+	o.logY = y
+}
+
+func (o *Output) Scatter(x, y float64) {
+	o.scatter = append(o.scatter, struct{ X, Y float64 }{
+		X: x,
+		Y: y,
+	})
+}
+
+func (o *Output) Histogram(values []float64, bins int) {
+	o.hist = append(o.hist[:0], values...)
+	o.bins = bins
+}
+
+func (o *Output) Heatmap(matrix [][]float64) {
+	for r := range matrix {
+		if len(matrix[r]) != len(matrix[0]) {
+			o.Fatalf("Heatmap row %d has %d values, row 0 has %d\n", r, len(matrix[r]), len(matrix[0]))
+		}
+	}
+	o.heatmap = matrix
+}
+
+func (o *Output) DrawGrid(grid [][]byte, palette map[byte]color.Color) {
+	o.grid = copyGrid(grid)
+	o.palette = palette
+}
+
+func (o *Output) Frame(grid [][]byte, palette map[byte]color.Color) {
+	o.frames = append(o.frames, copyGrid(grid))
+	o.palette = palette
+}
+
+func (o *Output) DrawGraph(g *graph.Graph, highlights ...[]int) {
+	o.graph = graphDot(g, highlights)
+}
+
 func (o *Output) init(input *Input, caseN int) {
 	o.input = input
 	o.caseN = caseN
+	o.start = time.Now()
+	o.everyPeriod = nil
+	o.sparkline = o.sparkline[:0]
+	o.best.reset()
+}
+
+func (o *Output) debugPrefix() string {
+	return "[case " + strconv.Itoa(o.caseN) + " +" + formatDuration(time.Since(o.start).Nanoseconds()) + "] "
+}
+
+func (o *Output) reset() {
+	o.output.Reset()
+	o.debugValues = nil
+	o.multiline = false
+	o.lines = 0
+	o.resetCharts()
+}
+
+func (o *Output) resetCharts() {
+	o.points = o.points[:0]
+	o.series = o.series[:0]
+	o.scatter = o.scatter[:0]
+	o.hist = o.hist[:0]
+	o.heatmap = nil
+	o.grid = nil
+	o.graph = nil
+	o.frames = nil
+	o.chartTitle = ""
+	o.xLabel = ""
+	o.yLabel = ""
+	o.logX = false
+	o.logY = false
 }
 
 func (o *Output) flush() {
+	o.flushDebugJSON()
+	if o.interactive {
+		return
+	}
+	if o.raw {
+		o.w.Write(o.output.Bytes())
+		o.writeCaseFile(o.output.Bytes())
+	} else {
+		o.writeCase()
+	}
+	o.output.Reset()
+	o.multiline = false
+	o.lines = 0
+	if o.autoFlush {
+		o.Flush()
+	}
+}
+
+func (o *Output) writeCase() {
 	if o.output.Len() <= 0 {
 		o.Fatal("No output")
 	}
-	fmt.Fprintf(o.w, "Case #%d:", o.caseN)
-	if !unicode.In(rune(o.output.Bytes()[0]), unicode.White_Space) {
-		o.w.Write([]byte{' '})
+	if o.lines > 0 {
+		if n := bytes.Count(bytes.Trim(o.output.Bytes(), "\n"), []byte{'\n'}) + 1; n != o.lines {
+			o.Fatalf("Output has %d lines, %d declared with Multiline\n", n, o.lines)
+		}
+	}
+	o.caseBuffer.Reset()
+	if !o.noPrefix {
+		header := append(o.scratch[:0], o.prefixBefore...)
+		if o.prefixNumber {
+			header = strconv.AppendInt(header, int64(o.caseN), 10)
+		}
+		header = append(header, o.prefixAfter...)
+		first := o.output.Bytes()[0]
+		ownLine := o.prefixLine || o.multiline
+		if ownLine && first != '\n' {
+			header = append(header, '\n')
+		} else if !ownLine && !unicode.In(rune(first), unicode.White_Space) {
+			header = append(header, ' ')
+		}
+		o.caseBuffer.Write(header)
 	}
-	o.w.Write(o.output.Bytes())
+	o.caseBuffer.Write(o.output.Bytes())
 	if o.output.Bytes()[o.output.Len()-1] != '\n' {
-		o.w.Write([]byte{'\n'})
+		o.caseBuffer.WriteByte('\n')
+	}
+	o.w.Write(o.caseBuffer.Bytes())
+	o.writeCaseFile(o.caseBuffer.Bytes())
+}
+
+func (o *Output) writeCaseFile(data []byte) {
+	if o.caseFn == "" {
+		return
+	}
+	if err := ioutil.WriteFile(o.caseFn+strconv.Itoa(o.caseN)+".out", data, 0644); err != nil {
+		log.Fatalln("Error writing case output file:", err)
 	}
-	o.output.Reset()
 }
 
 func (o *Output) assertOutput(fatal []bool, a ...interface{}) {
@@ -186,10 +674,33 @@ func (o *Output) AssertCount(count int, fatal ...bool) {
 	}
 }
 
-func (o *Output) AssertEqual(data string, fatal ...bool) {
-	if strings.TrimSpace(string(o.output.Bytes())) != strings.TrimSpace(data) {
-		o.assertOutputf(fatal, "Output should be: %q", data)
+func (o *Output) diff(correct string, same func(p, c string) bool) string {
+	diff := tokenDiff(string(o.output.Bytes()), correct, same, useColor())
+	if diff == "" {
+		return fmt.Sprintf("Output should be: %q", correct)
+	}
+	return diff
+}
+
+func (o *Output) AssertTolerance(abs, rel float64) {
+	o.absTolerance = abs
+	o.relTolerance = rel
+}
+
+func (o *Output) AssertIgnoreCase() {
+	o.ignoreCase = true
+}
+
+func (o *Output) assertSame(p, c string) bool {
+	return tokenSame(p, c, o.absTolerance, o.relTolerance, o.ignoreCase)
+}
+
+func (o *Output) AssertEqual(data string, fatal ...bool) bool {
+	if diff := tokenDiff(string(o.output.Bytes()), data, o.assertSame, useColor()); diff != "" {
+		o.assertOutput(fatal, diff)
+		return false
 	}
+	return true
 }
 
 func (o *Output) AssertIntEqual(a, b int, fatal ...bool) {
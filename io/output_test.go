@@ -1,7 +1,15 @@
 package io
 
 import (
+	"bufio"
 	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -38,3 +46,446 @@ func TestOutput(t *testing.T) {
 	assert.Equal(t, "Case #3: test 1\ntest1\n", string(b.Bytes()))
 	b.Reset()
 }
+
+func TestOutputPrefix(t *testing.T) {
+	b := &bytes.Buffer{}
+	o := newOutput(b)
+	o.init(nil, 7)
+	o.setPrefix("Case #%d:")
+	o.prefixLine = true
+	o.Print("1 2")
+	o.flush()
+	o.Print("\n3")
+	o.flush()
+
+	o.setPrefix("Test %d -")
+	o.prefixLine = false
+	o.Print("4")
+	o.flush()
+
+	o.noPrefix = true
+	o.Print("5")
+	o.flush()
+	assert.Equal(t, "Case #7:\n1 2\nCase #7:\n3\nTest 7 - 4\n5\n", b.String())
+}
+
+func TestOutputLines(t *testing.T) {
+	b := &bytes.Buffer{}
+	o := newOutput(b)
+	o.init(nil, 2)
+	o.Lines("#.", ".#")
+	o.flush()
+
+	o.Multiline(3)
+	o.Print(2)
+	o.Lines("R 1", "L 2")
+	o.flush()
+
+	o.Print("single")
+	o.flush()
+	assert.Equal(t, "Case #2:\n#.\n.#\nCase #2:\n2\nR 1\nL 2\nCase #2: single\n", b.String())
+}
+
+func TestOutputRaw(t *testing.T) {
+	b := &bytes.Buffer{}
+	o := newOutput(b)
+	o.init(nil, 1)
+	o.Raw("header ", 3, "\n")
+	o.Print(1)
+	o.flush()
+
+	o.raw = true
+	o.flush()
+	o.Print(2, " ")
+	o.flush()
+	assert.Equal(t, "header 3\nCase #1: 1\n2 ", b.String())
+}
+
+func TestOutputPrintf(t *testing.T) {
+	b := &bytes.Buffer{}
+	logs := &bytes.Buffer{}
+	log.SetOutput(logs)
+	defer log.SetOutput(os.Stderr)
+
+	o := newOutput(b)
+	o.init(initInput("1 2"), 4)
+	o.Printf("%d %s", 5, "x")
+	o.Debugf("checked %d", 2)
+	o.Printf("\n%.2f\n", 1.5)
+	o.flush()
+	assert.Equal(t, "Case #4: 5 x\n1.50\n", b.String())
+	assert.Regexp(t, `\[case 4 \+[0-9.]+[mun]?s\] input: \[\], output: "5 x"`, logs.String())
+	assert.Regexp(t, `\[case 4 \+[0-9.]+[mun]?s\] checked 2`, logs.String())
+}
+
+func TestOutputVerbosity(t *testing.T) {
+	logs := &bytes.Buffer{}
+	log.SetOutput(logs)
+	defer log.SetOutput(os.Stderr)
+
+	o := newOutput(&bytes.Buffer{})
+	o.init(initInput(""), 1)
+	o.Debug("debug")
+	o.Debugv("verbose")
+	o.Tracef("trace %d", 3)
+	assert.Contains(t, logs.String(), "debug")
+	assert.NotContains(t, logs.String(), "verbose")
+
+	logs.Reset()
+	o.verbosity = 3
+	o.Debugvf("verbose %d", 2)
+	o.Trace("trace")
+	assert.Contains(t, logs.String(), "verbose 2")
+	assert.Contains(t, logs.String(), "trace")
+
+	logs.Reset()
+	o.verbosity = 0
+	o.Debug("debug")
+	assert.Empty(t, logs.String())
+}
+
+func TestOutputEveryPeriod(t *testing.T) {
+	o := newOutput(&bytes.Buffer{})
+	o.init(nil, 1)
+	calls := 0
+	o.EveryPeriod(func() {
+		calls++
+	})
+	o.Tick()
+	assert.Equal(t, 0, calls)
+
+	o.triggerPeriodic()
+	o.Tick()
+	o.Tick()
+	assert.Equal(t, 1, calls)
+
+	o.init(nil, 2)
+	o.triggerPeriodic()
+	o.Tick()
+	assert.Equal(t, 1, calls)
+}
+
+func TestOutputInteractive(t *testing.T) {
+	b := &bytes.Buffer{}
+	o := newOutput(b)
+	o.interactive = true
+	o.init(nil, 1)
+
+	o.Println(1, 2)
+	assert.Equal(t, "1 2\n", string(b.Bytes()))
+
+	o.Printf("%d\n", 3)
+	assert.Equal(t, "1 2\n3\n", string(b.Bytes()))
+
+	o.flush()
+	assert.Equal(t, "1 2\n3\n", string(b.Bytes()))
+}
+
+func TestOutputFlush(t *testing.T) {
+	b := &bytes.Buffer{}
+	w := bufio.NewWriter(b)
+	o := newOutput(w)
+	o.init(nil, 1)
+	o.Print(1)
+	o.flush()
+	assert.Equal(t, "", b.String())
+
+	o.Flush()
+	assert.Equal(t, "Case #1: 1\n", b.String())
+
+	o.autoFlush = true
+	o.Print(2)
+	o.flush()
+	assert.Equal(t, "Case #1: 1\nCase #1: 2\n", b.String())
+}
+
+func TestOutputQuiet(t *testing.T) {
+	b := &bytes.Buffer{}
+	o := newOutput(b)
+	o.quiet = true
+	o.init(nil, 2)
+
+	o.Debug("not printed")
+	o.triggerPeriodic()
+	assert.Equal(t, 0, len(o.periodicPrint))
+
+	o.Print("test")
+	o.flush()
+	assert.Equal(t, "Case #2: test\n", string(b.Bytes()))
+}
+
+func TestOutputReset(t *testing.T) {
+	b := &bytes.Buffer{}
+	o := newOutput(b)
+	o.init(nil, 1)
+
+	o.Print("partial")
+	o.PointInt(1, 2)
+	o.reset()
+	assert.Equal(t, 0, o.output.Len())
+	assert.Equal(t, 0, len(o.points))
+
+	o.Print("test")
+	o.flush()
+	assert.Equal(t, "Case #1: test\n", string(b.Bytes()))
+}
+
+type testName string
+
+func TestOutputPrintLikeFmt(t *testing.T) {
+	args := [][]interface{}{
+		{1, 2, "a", 3, true, 1.5, int64(-7)},
+		{"a", "b", testName("c"), 4, 5.0, []byte("x"), nil, uint8(3), []int{1, 2}},
+	}
+	for _, a := range args {
+		b := &bytes.Buffer{}
+		o := newOutput(b)
+		o.Print(a...)
+		assert.Equal(t, fmt.Sprint(a...), o.output.String())
+
+		o.reset()
+		o.Println(a...)
+		assert.Equal(t, fmt.Sprintln(a...), o.output.String())
+	}
+}
+
+func TestOutputBigInt(t *testing.T) {
+	b := &bytes.Buffer{}
+	o := newOutput(b)
+	o.init(nil, 1)
+
+	n, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	o.PrintBigInt(n)
+	o.Print(" ", n, big.NewInt(7))
+	o.flush()
+	assert.Equal(t, "Case #1: -123456789012345678901234567890 -123456789012345678901234567890 7\n", b.String())
+}
+
+func TestOutputPrintInts(t *testing.T) {
+	b := &bytes.Buffer{}
+	o := newOutput(b)
+	o.init(nil, 12)
+
+	o.PrintInt(-5)
+	o.Print(" ")
+	o.PrintInts([]int{1, 20, 300})
+	o.flush()
+	assert.Equal(t, "Case #12: -5 1 20 300\n", b.String())
+}
+
+func TestOutputYesNo(t *testing.T) {
+	b := &bytes.Buffer{}
+	o := newOutput(b)
+	o.init(nil, 1)
+	o.YesNo(true)
+	o.Print(" ")
+	o.PossibleImpossible(false)
+	o.flush()
+	assert.Equal(t, "Case #1: YES IMPOSSIBLE\n", b.String())
+
+	b.Reset()
+	odd := func(input *Input, output *Output) {
+		output.YesNo(input.Int()%2 == 1)
+	}
+	parser := newParser(odd, WithYesNo("Yes", "No"))
+	parser.quiet = true
+	parser.parse(strings.NewReader("2 1 2"), b)
+	assert.Equal(t, "Case #1: Yes\nCase #2: No\n", b.String())
+}
+
+func TestOutputFloat(t *testing.T) {
+	b := &bytes.Buffer{}
+	o := newOutput(b)
+	o.init(nil, 1)
+	o.Float(1e21, 2)
+	o.Print(" ")
+	o.Float(2.675, 2)
+	o.Print(" ")
+	o.Float(-0.0001, 3)
+	o.Print(" ")
+	o.Float(-0.5, 0)
+	o.precision = 3
+	o.Print(" ", 1e-7, 2.5)
+	o.flush()
+	assert.Equal(t, "Case #1: 1000000000000000000000.00 2.67 0.000 0 0.000 2.500\n", b.String())
+}
+
+func TestOutputMod(t *testing.T) {
+	b := &bytes.Buffer{}
+	o := newOutput(b)
+	o.init(nil, 1)
+	o.Mod(-3, 7)
+	o.Print(" ")
+	o.Mod(17, 7)
+	o.Print(" ")
+	o.Mod1e9_7(-1)
+	o.flush()
+	assert.Equal(t, "Case #1: 4 3 1000000006\n", b.String())
+}
+
+func TestOutputAssertEqual(t *testing.T) {
+	logs := &bytes.Buffer{}
+	log.SetOutput(logs)
+	defer log.SetOutput(os.Stderr)
+
+	o := newOutput(&bytes.Buffer{})
+	o.init(initInput(""), 1)
+	o.Print("1.0 Yes\n2")
+	assert.True(t, o.AssertEqual("1.0  Yes 2"))
+	assert.False(t, o.AssertEqual("1.0 YES 2"))
+	assert.Contains(t, logs.String(), "First difference at token 2, line 1")
+
+	o.AssertIgnoreCase()
+	assert.True(t, o.AssertEqual("1.0 YES 2"))
+	assert.False(t, o.AssertEqual("1.000001 YES 2"))
+	o.AssertTolerance(1e-5, 0)
+	assert.True(t, o.AssertEqual("1.000001 YES 2"))
+}
+
+func TestOutputJoin(t *testing.T) {
+	b := &bytes.Buffer{}
+	o := newOutput(b)
+	o.init(nil, 1)
+	o.Ints([]int{3, 1})
+	o.Print(" ")
+	o.Join(",", 1, "a", 2.5, int64(-4))
+	o.flush()
+	assert.Equal(t, "Case #1: 3 1 1,a,2.5,-4\n", b.String())
+}
+
+func BenchmarkOutputPrint(b *testing.B) {
+	o := newOutput(&bytes.Buffer{})
+	for i := 0; i < b.N; i++ {
+		o.Print(i, " ", i+1)
+		o.output.Reset()
+	}
+}
+
+func BenchmarkOutputFmt(b *testing.B) {
+	buffer := &bytes.Buffer{}
+	for i := 0; i < b.N; i++ {
+		fmt.Fprint(buffer, i, " ", i+1)
+		buffer.Reset()
+	}
+}
+
+func BenchmarkOutputPrintInts(b *testing.B) {
+	o := newOutput(&bytes.Buffer{})
+	ints := make([]int, 1000)
+	for i := range ints {
+		ints[i] = i * 1000
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		o.PrintInts(ints)
+		o.output.Reset()
+	}
+}
+
+func TestOutputPlot(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	o := newOutput(&bytes.Buffer{})
+	o.init(nil, 1)
+	o.Plot("best", 1, 5)
+	o.Plot("current", 1, 7)
+	o.Plot("best", 2, 4)
+	if assert.Len(t, o.series, 2) {
+		assert.Equal(t, "best", o.series[0].name)
+		assert.Equal(t, 2, len(o.series[0].points))
+		assert.Equal(t, 1, len(o.series[1].points))
+	}
+
+	parser := &Parser{baseFn: filepath.Join(dir, "A")}
+	parser.writeChart(o, 1)
+	_, err := os.Stat(filepath.Join(dir, "A1.png"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(o.series))
+}
+
+func TestOutputCharts(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	o := newOutput(&bytes.Buffer{})
+	o.init(nil, 2)
+	o.Scatter(1, 2)
+	o.Histogram([]float64{1, 2, 2, 3}, 3)
+	o.Heatmap([][]float64{{1, 2}, {3, 4}})
+
+	parser := &Parser{baseFn: filepath.Join(dir, "A")}
+	parser.writeChart(o, 2)
+	for _, fn := range []string{"A2.png", "A2.hist.png", "A2.heatmap.png"} {
+		_, err := os.Stat(filepath.Join(dir, fn))
+		assert.NoError(t, err, fn)
+	}
+	assert.Nil(t, o.heatmap)
+	assert.Equal(t, 0, len(o.hist))
+}
+
+func TestOutputEmptyHeatmap(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	o := newOutput(&bytes.Buffer{})
+	o.init(nil, 1)
+	o.Heatmap([][]float64{{}, {}})
+
+	parser := &Parser{baseFn: filepath.Join(dir, "A")}
+	parser.writeChart(o, 1)
+	_, err := os.Stat(filepath.Join(dir, "A1.heatmap.png"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestChartCustomization(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	o := newOutput(&bytes.Buffer{})
+	o.init(nil, 1)
+	o.ChartTitle("score")
+	o.ChartLabels("iteration", "value")
+	o.ChartLogScale(false, true)
+	o.Point(1, 10)
+	o.Point(2, 100)
+
+	parser := &Parser{baseFn: filepath.Join(dir, "A"), chartFormat: "svg"}
+	parser.chartWidth, parser.chartHeight = parseChartSize("6x3.5")
+	assert.Equal(t, 3.5, parser.chartHeight)
+	parser.writeChart(o, 1)
+
+	svg, err := ioutil.ReadFile(filepath.Join(dir, "A1.svg"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(svg), "score")
+	assert.Contains(t, string(svg), "iteration")
+	assert.Equal(t, "", o.chartTitle)
+	assert.False(t, o.logY)
+}
+
+func TestTimingChart(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	inputFn := filepath.Join(dir, "A.in")
+	writeTestFile(t, inputFn, "2\n1\n2\n")
+	parser := newParser(double, WithArgs(inputFn), WithNoProfile(), WithTimingChart())
+	parser.Run()
+
+	for _, fn := range []string{"A.timing.png", "A.memory.png"} {
+		_, err := os.Stat(filepath.Join(dir, fn))
+		assert.NoError(t, err, fn)
+	}
+}
+
+func TestPointsCSV(t *testing.T) {
+	o := newOutput(&bytes.Buffer{})
+	o.init(nil, 1)
+	o.Point(1, 2.5)
+	o.Plot("best", 1, 3)
+	o.Scatter(-1, 0)
+
+	b := &bytes.Buffer{}
+	assert.NoError(t, writePointsCSV(b, o))
+	assert.Equal(t, "series,x,y\n,1,2.5\nbest,1,3\nscatter,-1,0\n", b.String())
+}
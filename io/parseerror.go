@@ -0,0 +1,63 @@
+package io
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"sync/atomic"
+)
+
+type ParseError struct {
+	File   string
+	Offset int64
+	Line   int
+	Token  int64
+	Text   string
+	Type   string
+	Err    error
+}
+
+func (pe *ParseError) Error() string {
+	msg := fmt.Sprintf("%s:%d: token %d at byte %d: cannot parse %q as %s", pe.File, pe.Line, pe.Token, pe.Offset, pe.Text, pe.Type)
+	if pe.Err != nil {
+		msg += ": " + pe.Err.Error()
+	}
+	return msg
+}
+
+type positioner interface {
+	Position() (offset int64, line int)
+}
+
+func (i *Input) tokenPosition() (int64, int) {
+	if p, ok := i.scanner.(positioner); ok {
+		return p.Position()
+	}
+	return 0, 0
+}
+
+func (i *Input) fail(token []byte, typ string, err error) {
+	if ne, ok := err.(*strconv.NumError); ok {
+		err = ne.Err
+	}
+	offset, line := i.tokenPosition()
+	pe := &ParseError{
+		File:   i.fn,
+		Offset: offset,
+		Line:   line,
+		Token:  atomic.LoadInt64(&i.position),
+		Text:   string(token),
+		Type:   typ,
+		Err:    err,
+	}
+	if !i.returnErrors {
+		log.Fatalln("Error scanning input:", pe)
+	}
+	if i.err == nil {
+		i.err = pe
+	}
+}
+
+func (i *Input) Err() error {
+	return i.err
+}
@@ -0,0 +1,69 @@
+//go:build !submit
+// +build !submit
+
+package io
+
+import (
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+)
+
+func (parser *Parser) startProfile(i int) *os.File {
+	f, err := os.Create(parser.profileFn)
+	if err != nil {
+		log.Fatalln("Error opening profile file:", err)
+	}
+	if err := pprof.StartCPUProfile(f); err != nil {
+		parser.logCase(i, "Not profiling:", err)
+		f.Close()
+		return nil
+	}
+	return f
+}
+
+func (parser *Parser) stopProfile(f *os.File, i int) {
+	pprof.StopCPUProfile()
+	f.Close()
+
+	out, err := exec.Command("go", "tool", "pprof", "-top", os.Args[0], parser.profileFn).CombinedOutput()
+	if err != nil {
+		parser.logCase(i, "Not printing cpu profile, error running go tool pprof:", err)
+		return
+	}
+	parser.logCase(i, "CPUProfile:", string(out))
+
+	parser.writeFlameGraph(i)
+}
+
+func discardProfile(f *os.File) {
+	pprof.StopCPUProfile()
+	f.Close()
+}
+
+func (parser *Parser) heapProfileFn(i int) string {
+	return parser.baseFn + strconv.Itoa(i) + ".heap"
+}
+
+func (parser *Parser) writeHeapProfile(i int) {
+	fn := parser.heapProfileFn(i)
+	f, err := os.Create(fn)
+	if err != nil {
+		log.Fatalln("Error opening heap profile file:", err)
+	}
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		log.Fatalln("Error writing heap profile:", err)
+	}
+	f.Close()
+
+	out, err := exec.Command("go", "tool", "pprof", "-top", "-sample_index=alloc_space", os.Args[0], fn).CombinedOutput()
+	if err != nil {
+		parser.logCase(i, "Not printing heap profile, error running go tool pprof:", err)
+		return
+	}
+	parser.logCase(i, "HeapProfile:", string(out))
+}
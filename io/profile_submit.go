@@ -0,0 +1,19 @@
+//go:build submit
+// +build submit
+
+package io
+
+import "os"
+
+func (parser *Parser) startProfile(i int) *os.File {
+	return nil
+}
+
+func (parser *Parser) stopProfile(f *os.File, i int) {
+}
+
+func discardProfile(f *os.File) {
+}
+
+func (parser *Parser) writeHeapProfile(i int) {
+}
@@ -0,0 +1,30 @@
+//go:build !submit
+// +build !submit
+
+package io
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileWithoutPprof(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+	path := os.Getenv("PATH")
+	defer os.Setenv("PATH", path)
+	os.Setenv("PATH", dir)
+
+	parser := newParser(double)
+	parser.baseFn = filepath.Join(dir, "A")
+	parser.profileFn = parser.baseFn + ".prof"
+	parser.writeHeapProfile(1)
+	if f := parser.startProfile(1); f != nil {
+		parser.stopProfile(f, 1)
+	}
+	_, err := os.Stat(parser.heapProfileFn(1))
+	assert.NoError(t, err)
+}
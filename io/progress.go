@@ -0,0 +1,33 @@
+package io
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const progressWidth = 30
+
+func formatProgress(done, total int, elapsed time.Duration) string {
+	if total <= 0 {
+		return fmt.Sprintf("%d cases, elapsed %s", done, formatDuration(elapsed.Nanoseconds()))
+	}
+	filled := done * progressWidth / total
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressWidth-filled)
+	eta := time.Duration(0)
+	if done > 0 {
+		eta = elapsed / time.Duration(done) * time.Duration(total-done)
+	}
+	return fmt.Sprintf("[%s] %d/%d, elapsed %s, ETA %s", bar, done, total, formatDuration(elapsed.Nanoseconds()), formatDuration(eta.Nanoseconds()))
+}
+
+func (parser *Parser) printProgress(done, total int, start time.Time) {
+	if !parser.progress || parser.quiet {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\r%s\033[K", formatProgress(done, total, time.Since(start)))
+	if done == total {
+		fmt.Fprintln(os.Stderr)
+	}
+}
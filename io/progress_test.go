@@ -0,0 +1,14 @@
+package io
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatProgress(t *testing.T) {
+	assert.Equal(t, "[===============               ] 5/10, elapsed 2.00s, ETA 2.00s", formatProgress(5, 10, 2*time.Second))
+	assert.Equal(t, "[                              ] 0/10, elapsed 0.00ns, ETA 0.00ns", formatProgress(0, 10, 0))
+	assert.Equal(t, "3 cases, elapsed 1.00s", formatProgress(3, -1, time.Second))
+}
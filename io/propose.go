@@ -0,0 +1,49 @@
+package io
+
+import (
+	"fmt"
+	"sync"
+)
+
+type proposal struct {
+	sync.Mutex
+	answer string
+	score  float64
+	ok     bool
+}
+
+func (p *proposal) reset() {
+	p.Lock()
+	defer p.Unlock()
+	p.answer = ""
+	p.ok = false
+}
+
+func (p *proposal) get() (string, bool) {
+	p.Lock()
+	defer p.Unlock()
+	return p.answer, p.ok
+}
+
+func (o *Output) Propose(answer interface{}, score float64) bool {
+	o.best.Lock()
+	defer o.best.Unlock()
+	if o.best.ok && score <= o.best.score {
+		return false
+	}
+	o.best.answer = fmt.Sprint(answer)
+	o.best.score = score
+	o.best.ok = true
+	return true
+}
+
+func (o *Output) writeProposal() bool {
+	if o.output.Len() > 0 {
+		return false
+	}
+	answer, ok := o.best.get()
+	if ok {
+		o.output.WriteString(answer)
+	}
+	return ok
+}
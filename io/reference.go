@@ -0,0 +1,39 @@
+package io
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+)
+
+func (parser *Parser) runReference(input io.Reader) []byte {
+	args, err := splitCommand(parser.referenceCmd)
+	if err != nil {
+		log.Fatalln("Invalid reference command:", err)
+	}
+	if len(args) == 0 {
+		log.Fatalln("Empty reference command")
+	}
+
+	buffer := &bytes.Buffer{}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = input
+	cmd.Stdout = buffer
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		log.Fatalln("Error running reference solution:", err)
+	}
+	return buffer.Bytes()
+}
+
+func (parser *Parser) referenceOutput() *CompareOutput {
+	f, err := openInput(parser.inputFn)
+	if err != nil {
+		log.Fatalln("Error opening input file:", err)
+	}
+	defer f.Close()
+
+	return parser.newCompareOutput(bytes.NewReader(parser.runReference(f)))
+}
@@ -0,0 +1,24 @@
+package io
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParserReference(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	inputFn := filepath.Join(dir, "A.in")
+	writeTestFile(t, inputFn, "2\n1\n2\n")
+	referenceFn := filepath.Join(dir, "reference.sh")
+	writeTestFile(t, referenceFn, "printf 'Case #1: 2\\nCase #2: 5\\n'\n")
+
+	parser := newParser(double, WithArgs(inputFn), WithNoProfile(), WithReference("sh "+referenceFn))
+	parser.Run()
+
+	assert.Equal(t, []caseResult{{caseN: 1, verdict: OK}, {caseN: 2, verdict: WA}}, withoutMeasurements(parser.results))
+}
@@ -0,0 +1,95 @@
+package io
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+)
+
+const (
+	judgePrefix    = "judge: "
+	solutionPrefix = "solution: "
+)
+
+func parseTranscript(data []byte) ([]byte, []string) {
+	judge := &bytes.Buffer{}
+	solution := []string{}
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		switch {
+		case strings.HasPrefix(line, judgePrefix):
+			judge.WriteString(strings.TrimPrefix(line, judgePrefix))
+		case strings.HasPrefix(line, solutionPrefix):
+			solution = append(solution, strings.TrimSuffix(strings.TrimPrefix(line, solutionPrefix), "\n"))
+		}
+	}
+	return judge.Bytes(), solution
+}
+
+type replayWriter struct {
+	expected []string
+	line     int
+	partial  []byte
+	diverged bool
+}
+
+func (rw *replayWriter) Write(data []byte) (int, error) {
+	rw.partial = append(rw.partial, data...)
+	for {
+		idx := bytes.IndexByte(rw.partial, '\n')
+		if idx < 0 {
+			break
+		}
+		rw.check(string(rw.partial[:idx]))
+		rw.partial = rw.partial[idx+1:]
+	}
+	return len(data), nil
+}
+
+func (rw *replayWriter) check(line string) {
+	rw.line++
+	if rw.diverged {
+		return
+	}
+	expected := "<nothing>"
+	if rw.line <= len(rw.expected) {
+		expected = rw.expected[rw.line-1]
+		if line == expected {
+			return
+		}
+	}
+	rw.diverged = true
+	log.Printf("Replay diverged at solution line %d: recorded %q, got %q\n", rw.line, expected, line)
+}
+
+func (rw *replayWriter) finish() {
+	if len(rw.partial) > 0 {
+		rw.check(string(rw.partial))
+	}
+	if rw.diverged {
+		log.Fatalln("Replay verdict: diverged, judge responses after the divergence do not match the solution")
+	}
+	if rw.line < len(rw.expected) {
+		log.Fatalf("Replay verdict: solution wrote %d of %d recorded lines\n", rw.line, len(rw.expected))
+	}
+	log.Println("Replay verdict: matches recording")
+}
+
+func (parser *Parser) ParseReplay(fn string) {
+	data, err := ioutil.ReadFile(fn)
+	if err != nil {
+		log.Fatalln("Error reading transcript:", err)
+	}
+	judge, solution := parseTranscript(data)
+
+	parser.interactive = true
+	parser.baseFn = "replay"
+	parser.profileFn = parser.baseFn + ".prof"
+	parser.compareOutput = nil
+
+	rw := &replayWriter{expected: solution}
+	parser.parse(bytes.NewReader(judge), io.MultiWriter(os.Stdout, rw))
+	rw.finish()
+}
@@ -0,0 +1,83 @@
+package io
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type reportCase struct {
+	File     string `json:"file"`
+	Case     int    `json:"case"`
+	Verdict  string `json:"verdict"`
+	Duration int64  `json:"duration_ns"`
+	Memory   uint64 `json:"memory_bytes"`
+	Count    int    `json:"count"`
+}
+
+func (s *summary) reportCases() []reportCase {
+	s.Lock()
+	defer s.Unlock()
+
+	cases := []reportCase{}
+	for _, file := range s.files {
+		for _, r := range file.results {
+			cases = append(cases, reportCase{
+				File:     file.inputFn,
+				Case:     r.caseN,
+				Verdict:  r.verdict.String(),
+				Duration: r.duration.Nanoseconds(),
+				Memory:   r.memory,
+				Count:    r.count,
+			})
+		}
+	}
+	return cases
+}
+
+func writeReportJSON(w io.Writer, cases []reportCase) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(cases)
+}
+
+func writeReportCSV(w io.Writer, cases []reportCase) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"file", "case", "verdict", "duration_ns", "memory_bytes", "count"})
+	for _, c := range cases {
+		cw.Write([]string{
+			c.File,
+			strconv.Itoa(c.Case),
+			c.Verdict,
+			strconv.FormatInt(c.Duration, 10),
+			strconv.FormatUint(c.Memory, 10),
+			strconv.Itoa(c.Count),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (parser *Parser) writeReport() {
+	if parser.reportFn == "" || parser.summary == nil {
+		return
+	}
+
+	f, err := os.Create(parser.reportFn)
+	if err != nil {
+		log.Fatalln("Error creating report file:", err)
+	}
+	defer f.Close()
+
+	write := writeReportJSON
+	if strings.HasSuffix(parser.reportFn, ".csv") {
+		write = writeReportCSV
+	}
+	if err := write(f, parser.summary.reportCases()); err != nil {
+		log.Fatalln("Error writing report:", err)
+	}
+}
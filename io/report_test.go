@@ -0,0 +1,35 @@
+package io
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReport(t *testing.T) {
+	s := &summary{}
+	s.add("A.in", []caseResult{
+		{caseN: 1, verdict: OK, duration: time.Millisecond, memory: 2048, count: 5},
+	}, time.Millisecond)
+	cases := s.reportCases()
+
+	b := &bytes.Buffer{}
+	assert.NoError(t, writeReportCSV(b, cases))
+	assert.Equal(t, "file,case,verdict,duration_ns,memory_bytes,count\nA.in,1,OK,1000000,2048,5\n", b.String())
+
+	b.Reset()
+	assert.NoError(t, writeReportJSON(b, cases))
+	assert.Equal(t, `[
+  {
+    "file": "A.in",
+    "case": 1,
+    "verdict": "OK",
+    "duration_ns": 1000000,
+    "memory_bytes": 2048,
+    "count": 5
+  }
+]
+`, b.String())
+}
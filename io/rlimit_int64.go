@@ -0,0 +1,10 @@
+//go:build dragonfly || freebsd
+// +build dragonfly freebsd
+
+package io
+
+import "syscall"
+
+func newRlimit(cur, max uint64) *syscall.Rlimit {
+	return &syscall.Rlimit{Cur: int64(cur), Max: int64(max)}
+}
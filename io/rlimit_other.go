@@ -0,0 +1,18 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!solaris
+
+package io
+
+import (
+	"errors"
+	"runtime"
+	"time"
+)
+
+func setRlimits(cpuLimit time.Duration, memLimitMB int) error {
+	return errors.New("resource limits are not supported on " + runtime.GOOS)
+}
+
+func cpuLimitExceeded(err error) bool {
+	return false
+}
@@ -0,0 +1,10 @@
+//go:build aix || darwin || linux || netbsd || solaris
+// +build aix darwin linux netbsd solaris
+
+package io
+
+import "syscall"
+
+func newRlimit(cur, max uint64) *syscall.Rlimit {
+	return &syscall.Rlimit{Cur: cur, Max: max}
+}
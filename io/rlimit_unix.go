@@ -0,0 +1,35 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd solaris
+
+package io
+
+import (
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+func setRlimits(cpuLimit time.Duration, memLimitMB int) error {
+	if cpuLimit > 0 {
+		seconds := uint64((cpuLimit + time.Second - 1) / time.Second)
+		if err := syscall.Setrlimit(syscall.RLIMIT_CPU, newRlimit(seconds, seconds+1)); err != nil {
+			return err
+		}
+	}
+	if memLimitMB > 0 {
+		bytes := uint64(memLimitMB) << 20
+		if err := syscall.Setrlimit(syscall.RLIMIT_AS, newRlimit(bytes, bytes)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func cpuLimitExceeded(err error) bool {
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		return false
+	}
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+	return ok && status.Signaled() && status.Signal() == syscall.SIGXCPU
+}
@@ -0,0 +1,296 @@
+package io
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+)
+
+type caseJob struct {
+	caseN  int
+	input  *Input
+	output *Output
+}
+
+type runner struct {
+	parser *Parser
+	jobs   chan caseJob
+	done   chan Verdict
+
+	warningTimer      *time.Timer
+	startProfileTimer *time.Timer
+	stopProfileTimer  *time.Timer
+	timeLimitTimer    *time.Timer
+
+	periodicPrintTicker *time.Ticker
+	memSampleTicker     *time.Ticker
+}
+
+func newStoppedTimer() *time.Timer {
+	t := time.NewTimer(time.Hour)
+	stopTimer(t)
+	return t
+}
+
+func stopTimer(t *time.Timer) {
+	if !t.Stop() {
+		select {
+		case <-t.C:
+		default:
+		}
+	}
+}
+
+func resetTimer(t *time.Timer, d time.Duration) {
+	stopTimer(t)
+	t.Reset(d)
+}
+
+func stopTicker(t *time.Ticker) {
+	t.Stop()
+	select {
+	case <-t.C:
+	default:
+	}
+}
+
+func newRunner(parser *Parser) *runner {
+	r := &runner{
+		parser:              parser,
+		warningTimer:        newStoppedTimer(),
+		startProfileTimer:   newStoppedTimer(),
+		stopProfileTimer:    newStoppedTimer(),
+		timeLimitTimer:      newStoppedTimer(),
+		periodicPrintTicker: time.NewTicker(time.Second),
+		memSampleTicker:     time.NewTicker(memSampleInterval),
+	}
+	stopTicker(r.periodicPrintTicker)
+	stopTicker(r.memSampleTicker)
+	r.startWorker()
+	return r
+}
+
+func (r *runner) startWorker() {
+	r.jobs = make(chan caseJob)
+	r.done = make(chan Verdict, 1)
+	go func(jobs <-chan caseJob, done chan<- Verdict) {
+		for job := range jobs {
+			done <- r.parser.solveCase(job)
+		}
+	}(r.jobs, r.done)
+}
+
+func (r *runner) abandon() {
+	close(r.jobs)
+	r.startWorker()
+}
+
+func (r *runner) abandonCase(i int, input *Input, output *Output) bool {
+	parser := r.parser
+	newOutput := output.abandon()
+	if newOutput == nil {
+		return false
+	}
+	input.abandon()
+	parser.output = newOutput
+	r.abandon()
+	if answer, ok := output.best.get(); ok {
+		parser.logCase(i, "Writing best proposed answer")
+		parser.output.init(parser.input, i)
+		parser.output.Print(answer)
+		parser.output.flush()
+	}
+	return true
+}
+
+func (r *runner) stop() {
+	close(r.jobs)
+	r.stopTimers()
+}
+
+func (r *runner) stopTimers() {
+	stopTimer(r.warningTimer)
+	stopTimer(r.startProfileTimer)
+	stopTimer(r.stopProfileTimer)
+	stopTimer(r.timeLimitTimer)
+	stopTicker(r.periodicPrintTicker)
+	stopTicker(r.memSampleTicker)
+}
+
+func (r *runner) startTimers() {
+	parser := r.parser
+	if !parser.quiet {
+		resetTimer(r.warningTimer, 500*time.Millisecond)
+	}
+	if !parser.quiet && !parser.noProfile {
+		resetTimer(r.startProfileTimer, parser.profileStart)
+		if parser.profileStop > 0 {
+			resetTimer(r.stopProfileTimer, parser.profileStart+parser.profileStop)
+		}
+	}
+	if parser.timeLimit > 0 {
+		resetTimer(r.timeLimitTimer, parser.timeLimit)
+	}
+	r.periodicPrintTicker.Reset(parser.periodInterval())
+	r.memSampleTicker.Reset(memSampleInterval)
+}
+
+func (parser *Parser) caseInput(i int) *Input {
+	if !parser.skipTLE || parser.validator == nil {
+		return parser.input
+	}
+	tokenizer, ok := parser.input.scanner.(*Tokenizer)
+	if !ok {
+		return parser.input
+	}
+
+	position := parser.input.position
+	tokenizer.record()
+	parser.input.init(parser.caseSeed(i))
+	parser.validator(parser.input)
+
+	input := newInput(tokenizer.recorded())
+	input.position = position
+	input.fn = parser.input.fn
+	input.returnErrors = parser.input.returnErrors
+	return input
+}
+
+func (parser *Parser) logCase(i int, a ...interface{}) {
+	if parser.quiet {
+		return
+	}
+	log.Println(append([]interface{}{fmt.Sprintf("Case #%d:", i)}, a...)...)
+}
+
+func (parser *Parser) solveCase(job caseJob) (verdict Verdict) {
+	i, input, output := job.caseN, job.input, job.output
+	defer func() {
+		if r := recover(); r != nil {
+			output.Debugf("Panic: %v\n%s", r, debug.Stack())
+			output.reset()
+			verdict = RE
+		}
+	}()
+
+	output.init(input, i)
+	input.init(parser.caseSeed(i))
+	input.deadline = parser.caseDeadline(time.Now())
+
+	parser.f(input, output)
+
+	if !output.finish() {
+		return Unchecked
+	}
+	output.writeProposal()
+
+	verdict = Unchecked
+	if parser.compareOutput != nil && parser.compareOutput.HasOutput(i) {
+		verdict = parser.check(input, output, string(parser.compareOutput.GetOutput(i)))
+	} else if parser.caseHashes != nil && parser.caseHashes.HasOutput(i) {
+		verdict = parser.checkHash(output, i)
+	} else if parser.checker != nil {
+		verdict = parser.check(input, output, "")
+	}
+
+	if parser.summary != nil {
+		parser.summary.addAnswer(output.output.String())
+	}
+	output.flush()
+	parser.writeChart(output, i)
+	return verdict
+}
+
+func (r *runner) run(i int) caseResult {
+	parser := r.parser
+	if parser.gcBetween {
+		runtime.GC()
+	}
+
+	result := caseResult{
+		caseN: i,
+	}
+	startTime := time.Now()
+	memory := &peakMemory{}
+	memory.sample()
+
+	input, output := parser.caseInput(i), parser.output
+	startCount := output.periodicCount
+	startPosition := atomic.LoadInt64(&input.position)
+	r.startTimers()
+	output.begin()
+	r.jobs <- caseJob{
+		caseN:  i,
+		input:  input,
+		output: output,
+	}
+
+	var f *os.File
+	heapProfiled := false
+	interrupted := parser.interrupted
+
+loop:
+	for {
+		select {
+		case <-r.warningTimer.C:
+			parser.logCase(i, "Long calculation, peak memory", formatBytes(memory.sample()))
+		case <-r.startProfileTimer.C:
+			f = parser.startProfile(i)
+		case <-r.stopProfileTimer.C:
+			if f == nil {
+				continue
+			}
+			parser.stopProfile(f, i)
+			f = nil
+		case <-r.memSampleTicker.C:
+			memory.sample()
+		case <-r.periodicPrintTicker.C:
+			parser.output.triggerPeriodic()
+			if !parser.quiet && !heapProfiled && parser.overMemThreshold() {
+				parser.logCase(i, "Memory threshold exceeded")
+				parser.writeHeapProfile(i)
+				heapProfiled = true
+			}
+		case <-r.timeLimitTimer.C:
+			result.verdict = TLE
+			if parser.skipTLE && input != parser.input {
+				if r.abandonCase(i, input, output) {
+					parser.logCase(i, "Time limit exceeded, skipping")
+					break loop
+				}
+			}
+			parser.logCase(i, "Time limit exceeded")
+		case <-interrupted:
+			interrupted = nil
+			if r.abandonCase(i, input, output) {
+				parser.logCase(i, "Interrupted")
+				break loop
+			}
+		case verdict := <-r.done:
+			if result.verdict != TLE {
+				result.verdict = verdict
+			}
+			result.count = output.periodicCount - startCount
+			break loop
+		}
+	}
+	r.stopTimers()
+	result.duration = time.Since(startTime)
+	result.tokens = atomic.LoadInt64(&input.position) - startPosition
+	result.memory = memory.sample()
+	if !parser.quiet && !heapProfiled && parser.memProfile && parser.newHeapPeak(result.memory) {
+		parser.writeHeapProfile(i)
+	}
+
+	parser.output.resetPeriodic()
+	if f != nil && parser.profileStop == 0 {
+		parser.stopProfile(f, i)
+	} else if f != nil {
+		discardProfile(f)
+	}
+	return result
+}
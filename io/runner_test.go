@@ -0,0 +1,158 @@
+package io
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunnerSkipTLE(t *testing.T) {
+	f := func(input *Input, output *Output) {
+		n := input.Int()
+		if n == 2 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		if n == 3 {
+			panic("three")
+		}
+		output.Print(n, input.Int())
+	}
+	validator := func(input *Input) {
+		input.Int()
+		input.Int()
+	}
+
+	parser := newParser(f, WithTimeLimit(50*time.Millisecond), WithSkipTLE(), WithValidator(validator))
+	parser.quiet = true
+	buffer := &bytes.Buffer{}
+	parser.parse(strings.NewReader("4\n1 5\n2 6\n3 7\n4 8\n"), buffer)
+
+	assert.Equal(t, []caseResult{
+		{caseN: 1, verdict: Unchecked},
+		{caseN: 2, verdict: TLE},
+		{caseN: 3, verdict: RE},
+		{caseN: 4, verdict: Unchecked},
+	}, withoutMeasurements(parser.results))
+
+	time.Sleep(250 * time.Millisecond)
+	assert.Equal(t, "Case #1: 1 5\nCase #4: 4 8\n", buffer.String())
+}
+
+func TestRunnerSkipTLEWithoutValidator(t *testing.T) {
+	f := func(input *Input, output *Output) {
+		n := input.Int()
+		if n == 1 {
+			time.Sleep(100 * time.Millisecond)
+		}
+		output.Print(n)
+	}
+
+	parser := newParser(f, WithTimeLimit(20*time.Millisecond), WithSkipTLE())
+	parser.quiet = true
+	buffer := &bytes.Buffer{}
+	parser.parse(strings.NewReader("2\n1\n2\n"), buffer)
+
+	assert.Equal(t, TLE, parser.results[0].verdict)
+	assert.Equal(t, "Case #1: 1\nCase #2: 2\n", buffer.String())
+}
+
+func TestRunnerPropose(t *testing.T) {
+	f := func(input *Input, output *Output) {
+		n := input.Int()
+		output.Propose(n, 1)
+		output.Propose("worse", 0)
+		output.Propose(n*10, 2)
+		if n == 2 {
+			time.Sleep(200 * time.Millisecond)
+			output.Propose(n*100, 3)
+		}
+	}
+
+	parser := newParser(f, WithTimeLimit(50*time.Millisecond), WithSkipTLE(), WithValidator(func(input *Input) {
+		input.Int()
+	}))
+	parser.quiet = true
+	buffer := &bytes.Buffer{}
+	parser.parse(strings.NewReader("3\n1\n2\n3\n"), buffer)
+
+	time.Sleep(250 * time.Millisecond)
+	assert.Equal(t, "Case #1: 10\nCase #2: 20\nCase #3: 30\n", buffer.String())
+	assert.Equal(t, TLE, parser.results[1].verdict)
+	assert.Equal(t, int64(1), parser.results[0].tokens)
+}
+
+func TestRunnerGCPercent(t *testing.T) {
+	percent := 0
+	f := func(input *Input, output *Output) {
+		percent = debug.SetGCPercent(200)
+		debug.SetGCPercent(percent)
+		output.Print(input.Int())
+	}
+
+	before := debug.SetGCPercent(100)
+	defer debug.SetGCPercent(before)
+
+	parser := newParser(f, WithGCPercent(-1), WithGCBetweenCases())
+	parser.quiet = true
+	parser.parse(strings.NewReader("1\n1\n"), &bytes.Buffer{})
+
+	assert.Equal(t, -1, percent)
+	assert.Equal(t, 100, debug.SetGCPercent(100))
+}
+
+func TestRunnerInterrupt(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	inputFn := filepath.Join(dir, "A.in")
+	writeTestFile(t, inputFn, "3\n1\n2\n3\n")
+
+	release := make(chan struct{})
+	defer close(release)
+	var parser *Parser
+	f := func(input *Input, output *Output) {
+		n := input.Int()
+		if n == 2 {
+			output.Propose(n*10, 1)
+			close(parser.interrupted)
+			<-release
+			output.Debug("after interrupt")
+			input.Int()
+		}
+		output.Print(n)
+	}
+
+	parser = newParser(f, WithNoProfile())
+	parser.quiet = true
+	parser.interrupted = make(chan struct{})
+	parser.ParseFiles([]string{inputFn, inputFn})
+
+	assert.True(t, parser.isInterrupted())
+	assert.Len(t, parser.results, 2)
+	out, err := ioutil.ReadFile(filepath.Join(dir, "A.out.tmp"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Case #1: 1\nCase #2: 20\n", string(out))
+	_, err = os.Stat(filepath.Join(dir, "A.out"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRunnerAbandonFinishedCase(t *testing.T) {
+	parser := newParser(double)
+	parser.quiet = true
+	parser.output = newOutput(&bytes.Buffer{})
+	r := newRunner(parser)
+	defer r.stop()
+
+	output := parser.output
+	output.begin()
+	assert.True(t, output.finish())
+	assert.False(t, r.abandonCase(1, newInput(NewTokenizer(strings.NewReader(""))), output))
+	assert.Equal(t, output, parser.output)
+}
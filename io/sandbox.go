@@ -0,0 +1,164 @@
+package io
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (parser *Parser) sandboxArgs() []string {
+	return []string{
+		"run",
+		"-sandboxchild",
+		"-cpulimit", parser.cpuLimit.String(),
+		"-memlimit", strconv.Itoa(parser.memLimit),
+		"-seed", strconv.FormatInt(parser.seed, 10),
+		"-format", parser.format,
+		"-delimiters", parser.delimiters,
+		"-prefix", parser.casePrefix,
+		"-prefixline=" + strconv.FormatBool(parser.prefixLine),
+		"-noprefix=" + strconv.FormatBool(parser.noPrefix),
+		"-raw=" + strconv.FormatBool(parser.raw),
+		"-precision", strconv.Itoa(parser.precision),
+	}
+}
+
+func (parser *Parser) runSandboxChild() {
+	if err := setRlimits(parser.cpuLimit, parser.memLimit); err != nil {
+		log.Fatalln("Error setting resource limits:", err)
+	}
+	parser.quiet = true
+	parser.baseFn = "sandbox"
+	parser.compareOutput = nil
+	parser.parse(os.Stdin, os.Stdout)
+}
+
+func crashVerdict(err error, stderr []byte) Verdict {
+	if bytes.Contains(stderr, []byte("out of memory")) || bytes.Contains(stderr, []byte("cannot allocate memory")) {
+		return MLE
+	}
+	if cpuLimitExceeded(err) || bytes.Contains(stderr, []byte("SIGXCPU")) {
+		return TLE
+	}
+	return RE
+}
+
+func (parser *Parser) sandboxResults(input, stdout []byte, err error, stderr []byte) []caseResult {
+	tokenizer := NewTokenizer(bytes.NewReader(input))
+	tokenizer.SetDelimiters(parser.delimiters)
+	T := parser.readCaseCount(newInput(tokenizer))
+	if !parser.splitsCases() {
+		return parser.unsplitSandboxResults(T, err, stderr)
+	}
+
+	produced := parser.newCompareOutput(bytes.NewReader(stdout))
+	lastCase := 0
+	for caseN := range produced.outputs {
+		if caseN > lastCase {
+			lastCase = caseN
+		}
+	}
+	if T < 0 {
+		T = lastCase
+		if err != nil {
+			T++
+		}
+	}
+
+	results := []caseResult{}
+	for i := 1; i <= T; i++ {
+		if !parser.selected(i) {
+			continue
+		}
+		if !produced.HasOutput(i) && i < lastCase {
+			results = append(results, caseResult{caseN: i, verdict: RE})
+			continue
+		}
+		if !produced.HasOutput(i) {
+			verdict := RE
+			if err != nil {
+				verdict = crashVerdict(err, stderr)
+			}
+			results = append(results, caseResult{caseN: i, verdict: verdict})
+			if i < T {
+				log.Printf("Cases #%d to #%d not run after %s in case #%d\n", i+1, T, verdict, i)
+			}
+			break
+		}
+
+		verdict := Unchecked
+		output := string(produced.GetOutput(i))
+		if parser.compareOutput != nil && parser.compareOutput.HasOutput(i) {
+			verdict = WA
+			if parser.equal("", output, string(parser.compareOutput.GetOutput(i))) {
+				verdict = OK
+			}
+		}
+		results = append(results, caseResult{caseN: i, verdict: verdict})
+	}
+	return results
+}
+
+func (parser *Parser) unsplitSandboxResults(T int, err error, stderr []byte) []caseResult {
+	verdict := Unchecked
+	if err != nil {
+		verdict = crashVerdict(err, stderr)
+		log.Printf("Output without case prefix, the case where the child died is unknown, all cases are %s\n", verdict)
+	}
+	if T < 0 {
+		T = 1
+	}
+	results := []caseResult{}
+	for i := 1; i <= T; i++ {
+		if parser.selected(i) {
+			results = append(results, caseResult{caseN: i, verdict: verdict})
+		}
+	}
+	return results
+}
+
+func (parser *Parser) ParseSandboxed() {
+	input, err := readInput(parser.inputFn)
+	if err != nil {
+		log.Fatalln("Error reading input file:", err)
+	}
+	parser.compareOutput = nil
+	if parser.splitsCases() {
+		parser.compareOutput = parser.openOutput(parser.correctFn)
+	}
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd := exec.Command(os.Args[0], parser.sandboxArgs()...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	startTime := time.Now()
+	err = cmd.Run()
+	duration := time.Since(startTime)
+
+	if err := writeFileAtomic(parser.outputFn, stdout.Bytes()); err != nil {
+		log.Fatalln("Error writing output file:", err)
+	}
+	if err != nil && !parser.quiet {
+		lines := strings.Split(strings.TrimSpace(stderr.String()), "\n")
+		if len(lines) > transcriptTail {
+			lines = lines[len(lines)-transcriptTail:]
+		}
+		log.Printf("%s: %v\n%s\n", parser.inputFn, err, strings.Join(lines, "\n"))
+	}
+
+	if !parser.splitsCases() {
+		parser.checkWholeOutput()
+	}
+	parser.results = parser.sandboxResults(input, stdout.Bytes(), err, stderr.Bytes())
+	parser.summary.add(parser.inputFn, parser.results, duration)
+	if !parser.quiet {
+		log.Println("Total time:", formatDuration(duration.Nanoseconds()))
+		parser.logVerdicts()
+	}
+}
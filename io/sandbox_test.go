@@ -0,0 +1,52 @@
+package io
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCrashVerdict(t *testing.T) {
+	err := errors.New("exit status 2")
+	assert.Equal(t, MLE, crashVerdict(err, []byte("fatal error: runtime: out of memory")))
+	assert.Equal(t, TLE, crashVerdict(err, []byte("SIGXCPU: cpu limit exceeded")))
+	assert.Equal(t, RE, crashVerdict(err, []byte("panic: index out of range")))
+}
+
+func TestSandboxResults(t *testing.T) {
+	parser := newParser(double)
+	parser.quiet = true
+	parser.compareOutput = &CompareOutput{outputs: map[int][]byte{1: []byte(" 2\n"), 2: []byte(" 5\n")}}
+
+	input := []byte("5\n1\n2\n3\n4\n5\n")
+	stdout := []byte("Case #1: 2\nCase #2: 4\nCase #4: 8\n")
+	err := errors.New("exit status 2")
+
+	assert.Equal(t, []caseResult{
+		{caseN: 1, verdict: OK},
+		{caseN: 2, verdict: WA},
+		{caseN: 3, verdict: RE},
+		{caseN: 4, verdict: Unchecked},
+		{caseN: 5, verdict: MLE},
+	}, parser.sandboxResults(input, stdout, err, []byte("fatal error: runtime: out of memory")))
+}
+
+func TestSandboxArgs(t *testing.T) {
+	parser := newParser(double)
+	parser.noPrefix = true
+	parser.precision = 3
+	args := parser.sandboxArgs()
+	assert.Contains(t, args, "-noprefix=true")
+	assert.Contains(t, args, "-raw=false")
+	assert.Contains(t, args, "3")
+}
+
+func TestSandboxResultsNoPrefix(t *testing.T) {
+	parser := newParser(double, WithNoCasePrefix())
+	parser.quiet = true
+
+	input := []byte("2\n1\n2\n")
+	assert.Equal(t, []caseResult{{caseN: 1, verdict: Unchecked}, {caseN: 2, verdict: Unchecked}}, parser.sandboxResults(input, []byte("2\n4\n"), nil, nil))
+	assert.Equal(t, []caseResult{{caseN: 1, verdict: RE}, {caseN: 2, verdict: RE}}, parser.sandboxResults(input, []byte("2\n"), errors.New("exit status 2"), nil))
+}
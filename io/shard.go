@@ -0,0 +1,113 @@
+package io
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func parseShard(str string) (int, int) {
+	parts := strings.SplitN(str, "/", 2)
+	if len(parts) != 2 {
+		log.Fatalln("Invalid shard, expected i/n:", str)
+	}
+	index, err := strconv.Atoi(parts[0])
+	if err != nil {
+		log.Fatalln("Invalid shard index:", parts[0])
+	}
+	count, err := strconv.Atoi(parts[1])
+	if err != nil || count < 1 || index < 0 || index >= count {
+		log.Fatalln("Invalid shard, expected 0 <= i < n:", str)
+	}
+	return index, count
+}
+
+func (parser *Parser) inShard(i int) bool {
+	return parser.shardCount == 0 || (i-1)%parser.shardCount == parser.shardIndex
+}
+
+func (parser *Parser) shardSuffix() string {
+	if parser.shardCount == 0 {
+		return ""
+	}
+	return fmt.Sprintf(".shard%dof%d", parser.shardIndex, parser.shardCount)
+}
+
+func (parser *Parser) mergeFlags(flags *flag.FlagSet) {
+	flags.StringVar(&parser.mergeFn, "o", parser.mergeFn, "write merged output to file instead of stdout")
+}
+
+func (parser *Parser) mergeCommand(args []string) {
+	outputFns := parser.parseFlagSet(parser.newFlagSet("merge"), args)
+	if len(outputFns) == 0 {
+		log.Fatalln("You need to specify at least one output file")
+	}
+
+	merged := map[int][]byte{}
+	for _, fn := range outputFns {
+		co := parser.openOutput(fn)
+		if co == nil {
+			log.Fatalln("Output file does not exist:", fn)
+		}
+		for caseN, output := range co.outputs {
+			if _, ok := merged[caseN]; ok {
+				log.Fatalf("Case #%d is in more than one output file\n", caseN)
+			}
+			merged[caseN] = output
+		}
+	}
+
+	caseNs := make([]int, 0, len(merged))
+	for caseN := range merged {
+		caseNs = append(caseNs, caseN)
+	}
+	sort.Ints(caseNs)
+
+	var out io.Writer = os.Stdout
+	if parser.mergeFn != "" {
+		f, err := createAtomic(parser.mergeFn)
+		if err != nil {
+			log.Fatalln("Error creating output file:", err)
+		}
+		defer func() {
+			if err := f.commit(); err != nil {
+				log.Fatalln("Error writing output file:", err)
+			}
+		}()
+		out = f
+	}
+
+	before, after := parser.outputPrefix()
+	w := bufio.NewWriter(out)
+	defer w.Flush()
+	previous := 0
+	for _, caseN := range caseNs {
+		if caseN != previous+1 {
+			log.Printf("Cases #%d to #%d are missing\n", previous+1, caseN-1)
+		}
+		previous = caseN
+		fmt.Fprintf(w, "%s%d%s%s", before, caseN, after, merged[caseN])
+	}
+}
+
+type shardValue struct {
+	parser *Parser
+}
+
+func (sv shardValue) String() string {
+	if sv.parser == nil || sv.parser.shardCount == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", sv.parser.shardIndex, sv.parser.shardCount)
+}
+
+func (sv shardValue) Set(str string) error {
+	sv.parser.shardIndex, sv.parser.shardCount = parseShard(str)
+	return nil
+}
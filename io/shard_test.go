@@ -0,0 +1,59 @@
+package io
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseShard(t *testing.T) {
+	index, count := parseShard("1/3")
+	assert.Equal(t, 1, index)
+	assert.Equal(t, 3, count)
+}
+
+func TestParserShard(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	inputFn := filepath.Join(dir, "A.in")
+	writeTestFile(t, inputFn, "4\n1\n2\n3\n4\n")
+
+	solved := 0
+	f := func(input *Input, output *Output) {
+		solved++
+		double(input, output)
+	}
+	validator := func(input *Input) {
+		input.IntIn(1, 4)
+	}
+	for shard := 0; shard < 2; shard++ {
+		parser := newParser(f, WithArgs(inputFn), WithNoProfile(), WithShard(shard, 2), WithValidator(validator))
+		parser.Run()
+	}
+	assert.Equal(t, 4, solved)
+
+	out, err := ioutil.ReadFile(filepath.Join(dir, "A.shard0of2.out"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Case #1: 2\nCase #3: 6\n", string(out))
+
+	mergedFn := filepath.Join(dir, "A.out")
+	parser := newParser(double, WithArgs("merge", "-o", mergedFn, filepath.Join(dir, "A.shard0of2.out"), filepath.Join(dir, "A.shard1of2.out")))
+	parser.Run()
+
+	out, err = ioutil.ReadFile(mergedFn)
+	assert.NoError(t, err)
+	assert.Equal(t, "Case #1: 2\nCase #2: 4\nCase #3: 6\nCase #4: 8\n", string(out))
+
+	writeTestFile(t, filepath.Join(dir, "B.shard0of2.out"), "#1) 2\n")
+	writeTestFile(t, filepath.Join(dir, "B.shard1of2.out"), "#2) 4\n")
+	parser = newParser(double, WithArgs("merge", "-prefix", "#%d)", "-o", mergedFn, filepath.Join(dir, "B.shard0of2.out"), filepath.Join(dir, "B.shard1of2.out")))
+	parser.Run()
+
+	out, err = ioutil.ReadFile(mergedFn)
+	assert.NoError(t, err)
+	assert.Equal(t, "#1) 2\n#2) 4\n", string(out))
+}
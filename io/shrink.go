@@ -0,0 +1,66 @@
+package io
+
+import (
+	"log"
+	"math/rand"
+)
+
+const (
+	shrinkAttempts = 20
+	maxShift       = 62
+)
+
+type shrinkSource struct {
+	src   rand.Source
+	shift uint
+}
+
+func newShrinkSource(seed int64, shift uint) *shrinkSource {
+	return &shrinkSource{
+		src:   rand.NewSource(seed),
+		shift: shift,
+	}
+}
+
+func (ss *shrinkSource) Int63() int64 {
+	return ss.src.Int63() >> ss.shift
+}
+
+func (ss *shrinkSource) Seed(seed int64) {
+	ss.src.Seed(seed)
+}
+
+func (parser *Parser) shrink(r *rand.Rand, seeds []int64, input []byte, cases []string, produced, correct []byte) ([]byte, []byte, []byte) {
+	originalLen := len(input)
+
+	if len(seeds) > 1 {
+		for _, seed := range seeds {
+			caseInput, caseInputs := parser.generateCases([]int64{seed}, 0)
+			if p, c, failed := parser.mismatch(caseInput, caseInputs); failed {
+				seeds = []int64{seed}
+				input, produced, correct = caseInput, p, c
+				break
+			}
+		}
+	}
+
+	for shift := uint(1); shift <= maxShift; shift++ {
+		for attempt := 0; attempt < shrinkAttempts; attempt++ {
+			seed := seeds[0]
+			if attempt > 0 {
+				seed = r.Int63()
+			}
+
+			shrunk, shrunkCases := parser.generateCases([]int64{seed}, shift)
+			if len(shrunk) >= len(input) {
+				continue
+			}
+			if p, c, failed := parser.mismatch(shrunk, shrunkCases); failed {
+				input, produced, correct = shrunk, p, c
+			}
+		}
+	}
+
+	log.Printf("Shrunk failing input from %d to %d bytes\n", originalLen, len(input))
+	return input, produced, correct
+}
@@ -0,0 +1,30 @@
+package io
+
+const sparklineWidth = 60
+
+var sparklineBars = []rune("▁▂▃▄▅▆▇█")
+
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	min, max := values[0], values[0]
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+
+	line := make([]rune, len(values))
+	for j, v := range values {
+		bar := 0
+		if max > min {
+			bar = int((v - min) / (max - min) * float64(len(sparklineBars)-1))
+		}
+		line[j] = sparklineBars[bar]
+	}
+	return string(line)
+}
@@ -0,0 +1,29 @@
+package io
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "", sparkline(nil))
+	assert.Equal(t, "▁▁▁", sparkline([]float64{2, 2, 2}))
+	assert.Equal(t, "▁▄█▁", sparkline([]float64{0, 0.5, 1, 0}))
+}
+
+func TestOutputPeriodicSparkline(t *testing.T) {
+	o := newOutput(nil)
+	o.init(nil, 1)
+	for j := 0; j < sparklineWidth+5; j++ {
+		o.periodicPrint <- struct{}{}
+		o.PeriodicSparkline(float64(j))
+	}
+	assert.Len(t, o.sparkline, sparklineWidth)
+	assert.Equal(t, 5.0, o.sparkline[0])
+	o.PeriodicSparkline(100)
+	assert.Len(t, o.sparkline, sparklineWidth)
+
+	o.init(nil, 2)
+	assert.Len(t, o.sparkline, 0)
+}
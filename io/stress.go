@@ -0,0 +1,178 @@
+package io
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type GeneratorFunc func(r *rand.Rand, w io.Writer)
+
+const (
+	stressInputFn   = "stress.in"
+	stressCorrectFn = "stress.correct"
+)
+
+func (parser *Parser) requireGenerator() {
+	if parser.generator == nil {
+		log.Fatalln("No generator, use io.WithGenerator")
+	}
+}
+
+func caseSeeds(r *rand.Rand, cases int) []int64 {
+	seeds := make([]int64, cases)
+	for i := range seeds {
+		seeds[i] = r.Int63()
+	}
+	return seeds
+}
+
+func (parser *Parser) generate(r *rand.Rand, cases int) []byte {
+	input, _ := parser.generateCases(caseSeeds(r, cases), 0)
+	return input
+}
+
+func (parser *Parser) generateCases(seeds []int64, shift uint) ([]byte, []string) {
+	buffer := &bytes.Buffer{}
+	fmt.Fprintln(buffer, len(seeds))
+	cases := make([]string, len(seeds))
+	for i, seed := range seeds {
+		start := buffer.Len()
+		parser.generator(rand.New(newShrinkSource(seed, shift)), buffer)
+		cases[i] = strings.Join(strings.Fields(string(buffer.Bytes()[start:])), " ")
+	}
+	return buffer.Bytes(), cases
+}
+
+func (parser *Parser) mismatch(input []byte, cases []string) ([]byte, []byte, bool) {
+	produced, _ := parser.solve(parser.f, input)
+	var correct []byte
+	if parser.referenceCmd != "" {
+		correct = parser.runReference(bytes.NewReader(input))
+	} else {
+		correct, _ = parser.solve(parser.brute, input)
+	}
+	return produced, correct, !parser.outputsMatch(cases, produced, correct)
+}
+
+func (parser *Parser) solve(f TestCaseFunc, input []byte) ([]byte, []caseResult) {
+	solver := *parser
+	solver.f = f
+	solver.quiet = true
+	solver.noProfile = true
+	solver.skipTLE = parser.timeLimit > 0 && parser.validator != nil
+	solver.strict = false
+	solver.inputFn, solver.baseFn, solver.debugJSONFn = "", "solve", ""
+	solver.casePrefix, solver.prefixLine, solver.noPrefix, solver.raw, solver.caseOutputs = "", false, false, false, false
+	solver.compareOutput, solver.caseHashes, solver.checker = nil, nil, nil
+	solver.cases, solver.shardCount, solver.failedOnly = nil, 0, false
+	solver.summary, solver.dashboard, solver.notifier, solver.interrupted = nil, nil, nil, nil
+	buffer := &bytes.Buffer{}
+	solver.parse(bytes.NewReader(input), buffer)
+	return buffer.Bytes(), solver.results
+}
+
+func (parser *Parser) outputsMatch(cases []string, produced, correct []byte) bool {
+	producedCo := NewCompareOutput(bytes.NewReader(produced))
+	correctCo := NewCompareOutput(bytes.NewReader(correct))
+	if len(producedCo.outputs) != len(correctCo.outputs) {
+		return false
+	}
+
+	for i, correctOutput := range correctCo.outputs {
+		if !producedCo.HasOutput(i) {
+			return false
+		}
+		caseInput := ""
+		if i >= 1 && i <= len(cases) {
+			caseInput = cases[i-1]
+		}
+		if !parser.equal(caseInput, string(producedCo.GetOutput(i)), string(correctOutput)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (parser *Parser) genCommand(args []string) {
+	flags := parser.newFlagSet("gen")
+	cases := 100
+	if args := parser.parseFlagSet(flags, args); len(args) > 0 {
+		var err error
+		cases, err = strconv.Atoi(args[0])
+		if err != nil {
+			log.Fatalln("Invalid number of cases:", args[0])
+		}
+	}
+	parser.requireGenerator()
+
+	log.Println("Seed:", parser.seed)
+	os.Stdout.Write(parser.generate(rand.New(rand.NewSource(parser.seed)), cases))
+}
+
+func (parser *Parser) stressFlags(flags *flag.FlagSet) {
+	flags.IntVar(&parser.stressIterations, "iterations", parser.stressIterations, "number of generated inputs, 0 to run until first mismatch")
+	flags.IntVar(&parser.stressCases, "cases", parser.stressCases, "number of cases per generated input")
+	flags.BoolVar(&parser.stressShrink, "shrink", parser.stressShrink, "minimize the failing input before reporting it")
+}
+
+func (parser *Parser) stressCommand(args []string) {
+	parser.parseFlagSet(parser.newFlagSet("stress"), args)
+
+	parser.requireGenerator()
+	if parser.brute == nil && parser.referenceCmd == "" {
+		log.Fatalln("No brute force solution, use io.WithBrute or io.WithReference")
+	}
+
+	log.Println("Seed:", parser.seed)
+	r := rand.New(rand.NewSource(parser.seed))
+	lastReport := time.Now()
+	for it := 1; parser.stressIterations == 0 || it <= parser.stressIterations; it++ {
+		seeds := caseSeeds(r, parser.stressCases)
+		input, inputCases := parser.generateCases(seeds, 0)
+		produced, correct, failed := parser.mismatch(input, inputCases)
+
+		if failed {
+			if parser.stressShrink {
+				input, produced, correct = parser.shrink(r, seeds, input, inputCases, produced, correct)
+			}
+			parser.writeStressFiles(input, correct)
+			log.Printf("Mismatch in iteration %d\nInput:\n%sOutput:\n%sBrute force:\n%s", it, input, produced, correct)
+			log.Fatalf("Input written to %s, brute force output to %s\n", stressInputFn, stressCorrectFn)
+		}
+
+		if time.Since(lastReport) >= time.Second {
+			log.Println("Iterations:", it)
+			lastReport = time.Now()
+		}
+	}
+	log.Println("No mismatch in", parser.stressIterations, "iterations")
+}
+
+func (parser *Parser) writeStressFiles(input, correct []byte) {
+	if err := ioutil.WriteFile(stressInputFn, input, 0644); err != nil {
+		log.Fatalln("Error writing stress input:", err)
+	}
+	if err := ioutil.WriteFile(stressCorrectFn, correct, 0644); err != nil {
+		log.Fatalln("Error writing stress correct output:", err)
+	}
+}
+
+func Stress(gen GeneratorFunc, fast, brute TestCaseFunc, opts ...Option) {
+	log.SetFlags(0)
+
+	parser := newParser(fast, append(opts, WithGenerator(gen), WithBrute(brute))...)
+	args := parser.args
+	if len(args) > 0 && args[0] == "stress" {
+		args = args[1:]
+	}
+	parser.stressCommand(args)
+}
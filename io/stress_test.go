@@ -0,0 +1,93 @@
+package io
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	parser := newParser(double, WithGenerator(func(r *rand.Rand, w io.Writer) {
+		fmt.Fprintln(w, r.Intn(10))
+	}))
+	input := parser.generate(rand.New(rand.NewSource(1)), 3)
+	assert.Equal(t, input, parser.generate(rand.New(rand.NewSource(1)), 3))
+
+	output, results := parser.solve(double, []byte("2\n1\n5\n"))
+	assert.Equal(t, "Case #1: 2\nCase #2: 10\n", string(output))
+	assert.Equal(t, 2, len(results))
+
+	parser = newParser(double, WithInputFormat(FormatEOF), WithDelimiters(","), WithCasePrefix("#%d", false))
+	output, _ = parser.solve(double, []byte("3,4\n"))
+	assert.Equal(t, "Case #1: 6\nCase #2: 8\n", string(output))
+}
+
+func TestOutputsMatch(t *testing.T) {
+	parser := newParser(double)
+	assert.True(t, parser.outputsMatch(nil, []byte("Case #1: 2\n"), []byte("Case #1:  2 \n")))
+	assert.False(t, parser.outputsMatch(nil, []byte("Case #1: 2\n"), []byte("Case #1: 3\n")))
+	assert.False(t, parser.outputsMatch(nil, []byte(""), []byte("Case #1: 3\n")))
+
+	parser.checker = func(input, produced, correct string) bool {
+		return true
+	}
+	assert.True(t, parser.outputsMatch(nil, []byte("Case #1: 2\n"), []byte("Case #1: 3\n")))
+
+	parser.checker = func(input, produced, correct string) bool {
+		return produced == input
+	}
+	assert.True(t, parser.outputsMatch([]string{"2 a", "3"}, []byte("Case #1: 2 a\nCase #2: 3\n"), []byte("Case #1: x\nCase #2: y\n")))
+	assert.False(t, parser.outputsMatch([]string{"2 a", "3"}, []byte("Case #1: 3\nCase #2: 2 a\n"), []byte("Case #1: x\nCase #2: y\n")))
+}
+
+func TestStress(t *testing.T) {
+	generated := 0
+	gen := func(r *rand.Rand, w io.Writer) {
+		generated++
+		fmt.Fprintln(w, r.Intn(100))
+	}
+	sum := func(input *Input, output *Output) {
+		a := input.Int()
+		output.Print(a + a)
+	}
+	Stress(gen, double, sum, WithArgs("-iterations", "20", "-cases", "2"))
+	assert.Equal(t, 40, generated)
+}
+
+func TestShrink(t *testing.T) {
+	gen := func(r *rand.Rand, w io.Writer) {
+		n := 1 + r.Intn(1000)
+		fmt.Fprintln(w, n)
+		for i := 0; i < n; i++ {
+			fmt.Fprint(w, r.Intn(1000000), " ")
+		}
+		fmt.Fprintln(w)
+	}
+	sum := func(skipOdd bool) TestCaseFunc {
+		return func(input *Input, output *Output) {
+			s := 0
+			for _, a := range input.SliceInt(input.Int()) {
+				if !skipOdd || a%2 == 0 {
+					s += a
+				}
+			}
+			output.Print(s)
+		}
+	}
+
+	parser := newParser(sum(true), WithGenerator(gen), WithBrute(sum(false)))
+	r := rand.New(rand.NewSource(1))
+	seeds := caseSeeds(r, 3)
+	input, cases := parser.generateCases(seeds, 0)
+	assert.Equal(t, 3, len(cases))
+	produced, correct, failed := parser.mismatch(input, cases)
+	assert.True(t, failed)
+
+	shrunk, _, _ := parser.shrink(r, seeds, input, cases, produced, correct)
+	_, _, failed = parser.mismatch(shrunk, nil)
+	assert.True(t, failed)
+	assert.True(t, len(shrunk) < 20, string(shrunk))
+}
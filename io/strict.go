@@ -0,0 +1,28 @@
+package io
+
+import "log"
+
+func (i *Input) atLineEnd() bool {
+	le, ok := i.scanner.(interface {
+		AtLineEnd() bool
+	})
+	return !ok || le.AtLineEnd()
+}
+
+func (parser *Parser) checkCaseConsumed(result caseResult) bool {
+	if result.verdict.failed() || parser.input.atLineEnd() {
+		return true
+	}
+	parser.logCase(result.caseN, "Input not consumed to the end of line, next token", parser.input.position+1, "belongs to this case")
+	return false
+}
+
+func (parser *Parser) checkFileConsumed(consumed bool) {
+	if parser.input.HasNext() {
+		log.Printf("%s: input left after the last case at token %d\n", parser.inputFn, parser.input.position+1)
+		consumed = false
+	}
+	if !consumed && parser.summary != nil {
+		parser.summary.addFailedFile(parser.inputFn)
+	}
+}
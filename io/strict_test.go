@@ -0,0 +1,28 @@
+package io
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrictInput(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+
+	inputFn := filepath.Join(dir, "A.in")
+	writeTestFile(t, inputFn, "2\n1 2\n3 4\n")
+
+	parser := newParser(double, WithArgs(inputFn), WithNoProfile(), WithStrictInput())
+	parser.Run()
+	assert.Equal(t, []string{inputFn}, parser.summary.failedFiles)
+
+	sum := func(input *Input, output *Output) {
+		output.Print(input.Int() + input.Int())
+	}
+	parser = newParser(sum, WithArgs(inputFn), WithNoProfile(), WithStrictInput())
+	parser.Run()
+	assert.Empty(t, parser.summary.failedFiles)
+}
@@ -0,0 +1,186 @@
+package io
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+type fileSummary struct {
+	inputFn  string
+	results  []caseResult
+	duration time.Duration
+}
+
+type summary struct {
+	sync.Mutex
+	files       []fileSummary
+	failedFiles []string
+	flameGraphs []string
+	answers     answerStats
+}
+
+func (s *summary) addFlameGraph(fn string) {
+	s.Lock()
+	defer s.Unlock()
+	s.flameGraphs = append(s.flameGraphs, fn)
+}
+
+func (s *summary) addFailedFile(inputFn string) {
+	s.Lock()
+	defer s.Unlock()
+	s.failedFiles = append(s.failedFiles, inputFn)
+}
+
+func (s *summary) add(inputFn string, results []caseResult, duration time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+	s.files = append(s.files, fileSummary{
+		inputFn:  inputFn,
+		results:  results,
+		duration: duration,
+	})
+}
+
+func (s *summary) allResults() []caseResult {
+	results := []caseResult{}
+	for _, file := range s.files {
+		results = append(results, file.results...)
+	}
+	return results
+}
+
+func (s *summary) String() string {
+	s.Lock()
+	defer s.Unlock()
+
+	buffer := &bytes.Buffer{}
+	tw := tabwriter.NewWriter(buffer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "File\tCase\tVerdict\tTime\tPeak memory\t")
+
+	var duration time.Duration
+	var maxMemory uint64
+	for _, file := range s.files {
+		for _, r := range file.results {
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t\n", file.inputFn, r.caseN, r.verdict, formatDuration(r.duration.Nanoseconds()), formatBytes(r.memory))
+			if r.memory > maxMemory {
+				maxMemory = r.memory
+			}
+		}
+		duration += file.duration
+	}
+	tw.Flush()
+
+	results := s.allResults()
+	fmt.Fprintf(buffer, "Total: %d cases", len(results))
+	if verdicts := formatVerdicts(results); verdicts != "" {
+		fmt.Fprintf(buffer, ", %s", verdicts)
+	}
+	fmt.Fprintf(buffer, ", time %s, peak memory %s", formatDuration(duration.Nanoseconds()), formatBytes(maxMemory))
+	if answers := s.answers.String(); answers != "" {
+		fmt.Fprintf(buffer, "\n%s", answers)
+	}
+	if len(s.failedFiles) > 0 {
+		fmt.Fprintf(buffer, "\nFailed files: %s", strings.Join(s.failedFiles, ", "))
+	}
+	if len(s.flameGraphs) > 0 {
+		fmt.Fprintf(buffer, "\nFlame graphs: %s", strings.Join(s.flameGraphs, ", "))
+	}
+	return buffer.String()
+}
+
+func (s *summary) slowestString(n int) string {
+	s.Lock()
+	defer s.Unlock()
+
+	type fileResult struct {
+		inputFn string
+		caseResult
+	}
+	results := []fileResult{}
+	for _, file := range s.files {
+		for _, r := range file.results {
+			results = append(results, fileResult{file.inputFn, r})
+		}
+	}
+	if n > len(results) {
+		n = len(results)
+	}
+	if n <= 0 {
+		return ""
+	}
+	sort.SliceStable(results, func(a, b int) bool {
+		return results[a].duration > results[b].duration
+	})
+
+	parts := make([]string, 0, n)
+	for _, r := range results[:n] {
+		parts = append(parts, fmt.Sprintf("%s #%d %s (%d tokens)", r.inputFn, r.caseN, formatDuration(r.duration.Nanoseconds()), r.tokens))
+	}
+	return "Slowest: " + strings.Join(parts, ", ")
+}
+
+func (parser *Parser) hasFailures() bool {
+	if parser.summary == nil {
+		return false
+	}
+	parser.summary.Lock()
+	defer parser.summary.Unlock()
+	if len(parser.summary.failedFiles) > 0 {
+		return true
+	}
+	for _, r := range parser.summary.allResults() {
+		if r.verdict.failed() {
+			return true
+		}
+	}
+	return false
+}
+
+func (parser *Parser) printSummary() {
+	if parser.summary == nil || !parser.showSummary || parser.quiet {
+		return
+	}
+	log.Println(parser.summary)
+	if slowest := parser.summary.slowestString(parser.slowest); slowest != "" {
+		log.Println(slowest)
+	}
+	if parser.hasFailures() {
+		log.Println("Seed:", parser.seed)
+	}
+}
+
+const memSampleInterval = 10 * time.Millisecond
+
+type peakMemory struct {
+	peak uint64
+}
+
+func (pm *peakMemory) sample() uint64 {
+	if memory := heapInUse(); memory > pm.peak {
+		pm.peak = memory
+	}
+	return pm.peak
+}
+
+func heapInUse() uint64 {
+	ms := runtime.MemStats{}
+	runtime.ReadMemStats(&ms)
+	return ms.HeapInuse
+}
+
+func formatBytes(b uint64) string {
+	var i int
+	bf := float64(b)
+	units := []string{"B", "KB", "MB", "GB"}
+	for i = 0; bf >= 1024 && i < len(units)-1; i++ {
+		bf /= 1024
+	}
+	return fmt.Sprintf("%.1f%s", bf, units[i])
+}
@@ -0,0 +1,48 @@
+package io
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummary(t *testing.T) {
+	s := &summary{}
+	s.add("A.in", []caseResult{
+		{caseN: 1, verdict: OK, duration: time.Millisecond, memory: 2048},
+		{caseN: 2, verdict: WA, duration: 2 * time.Second, memory: 3 << 20},
+	}, 3*time.Second)
+
+	assert.Equal(t, `File  Case  Verdict  Time    Peak memory  
+A.in  1     OK       1.00ms  2.0KB        
+A.in  2     WA       2.00s   3.0MB        
+Total: 2 cases, 1 OK, 1 WA, time 3.00s, peak memory 3.0MB`, s.String())
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "12.0B", formatBytes(12))
+	assert.Equal(t, "1.5KB", formatBytes(1536))
+	assert.Equal(t, "2048.0GB", formatBytes(2<<40))
+}
+
+func TestPeakMemory(t *testing.T) {
+	pm := &peakMemory{peak: 1 << 62}
+	assert.Equal(t, uint64(1<<62), pm.sample())
+
+	pm = &peakMemory{}
+	assert.True(t, pm.sample() > 0)
+}
+
+func TestSlowest(t *testing.T) {
+	s := &summary{}
+	s.add("A.in", []caseResult{
+		{caseN: 1, duration: time.Millisecond, tokens: 2},
+		{caseN: 2, duration: 3 * time.Second, tokens: 1000},
+		{caseN: 3, duration: time.Second, tokens: 10},
+	}, 4*time.Second)
+
+	assert.Equal(t, "Slowest: A.in #2 3.00s (1000 tokens), A.in #3 1.00s (10 tokens)", s.slowestString(2))
+	assert.Equal(t, "Slowest: A.in #2 3.00s (1000 tokens), A.in #3 1.00s (10 tokens), A.in #1 1.00ms (2 tokens)", s.slowestString(5))
+	assert.Equal(t, "", s.slowestString(0))
+}
@@ -0,0 +1,386 @@
+package io
+
+import (
+	"bytes"
+	"io"
+	"unicode/utf8"
+)
+
+const tokenizerBufferSize = 64 * 1024
+
+type Tokenizer struct {
+	r         io.Reader
+	buf       []byte
+	start     int
+	end       int
+	token     []byte
+	err       error
+	lineStart bool
+	delims    [256]bool
+
+	base        int64
+	line        int
+	tokenOffset int64
+	tokenLine   int
+
+	canUnscan     bool
+	prevStart     int
+	prevLine      int
+	prevLineStart bool
+
+	recording       bool
+	recordStart     int
+	recordOffset    int64
+	recordLine      int
+	recordLineStart bool
+}
+
+func NewTokenizer(r io.Reader) *Tokenizer {
+	return newTokenizerSize(r, tokenizerBufferSize)
+}
+
+func newTokenizerSize(r io.Reader, size int) *Tokenizer {
+	t := &Tokenizer{
+		r:         r,
+		buf:       make([]byte, size),
+		lineStart: true,
+		line:      1,
+	}
+	t.SetDelimiters("")
+	return t
+}
+
+func (t *Tokenizer) SetDelimiters(delims string) {
+	t.delims = [256]bool{}
+	for _, b := range []byte(" \n\r\t\v\f" + delims) {
+		t.delims[b] = true
+	}
+}
+
+func (t *Tokenizer) fill() bool {
+	if t.err != nil {
+		return false
+	}
+	keep := t.prevStart
+	if t.recording && t.recordStart < keep {
+		keep = t.recordStart
+	}
+	if keep > 0 {
+		copy(t.buf, t.buf[keep:t.end])
+		t.base += int64(keep)
+		t.end -= keep
+		t.start -= keep
+		t.prevStart -= keep
+		t.recordStart -= keep
+	}
+	if t.end == len(t.buf) {
+		buf := make([]byte, 2*len(t.buf))
+		copy(buf, t.buf[:t.end])
+		t.buf = buf
+	}
+
+	n, err := t.r.Read(t.buf[t.end:])
+	t.end += n
+	if err != nil {
+		t.err = err
+	}
+	return n > 0 || err == nil
+}
+
+func (t *Tokenizer) skipDelims() bool {
+	for {
+		for t.start < t.end && t.delims[t.buf[t.start]] {
+			if t.buf[t.start] == '\n' {
+				t.line++
+			}
+			t.start++
+		}
+		if t.start < t.end {
+			return true
+		}
+		if !t.fill() {
+			return false
+		}
+	}
+}
+
+func (t *Tokenizer) Scan() bool {
+	t.mark()
+	if !t.skipDelims() {
+		return false
+	}
+
+	i := t.start
+	for {
+		for i < t.end && !t.delims[t.buf[i]] {
+			i++
+		}
+		if i < t.end {
+			break
+		}
+		offset := i - t.start
+		ok := t.fill()
+		i = t.start + offset
+		if !ok {
+			break
+		}
+	}
+
+	t.markToken()
+	t.token = t.buf[t.start:i]
+	t.start = i
+	t.lineStart = false
+	return true
+}
+
+func (t *Tokenizer) ScanByte() bool {
+	t.mark()
+	if !t.skipDelims() {
+		return false
+	}
+
+	t.markToken()
+	t.token = t.buf[t.start : t.start+1]
+	t.start++
+	t.lineStart = false
+	return true
+}
+
+func (t *Tokenizer) ScanRune() bool {
+	t.mark()
+	if !t.skipDelims() {
+		return false
+	}
+	for !utf8.FullRune(t.buf[t.start:t.end]) && t.fill() {
+	}
+
+	_, size := utf8.DecodeRune(t.buf[t.start:t.end])
+	t.markToken()
+	t.token = t.buf[t.start : t.start+size]
+	t.start += size
+	t.lineStart = false
+	return true
+}
+
+func (t *Tokenizer) ScanDelimited(delims string) bool {
+	saved := t.delims
+	t.SetDelimiters(delims)
+	ok := t.Scan()
+	t.delims = saved
+	return ok
+}
+
+func (t *Tokenizer) ScanFixed(width int) bool {
+	t.mark()
+	for {
+		for t.start < t.end && (t.buf[t.start] == '\n' || t.buf[t.start] == '\r') {
+			if t.buf[t.start] == '\n' {
+				t.line++
+			}
+			t.start++
+		}
+		if t.start < t.end {
+			break
+		}
+		if !t.fill() {
+			return false
+		}
+	}
+
+	i := t.start
+	for {
+		for i < t.end && i-t.start < width && t.buf[i] != '\n' {
+			i++
+		}
+		if i < t.end || i-t.start == width {
+			break
+		}
+		offset := i - t.start
+		ok := t.fill()
+		i = t.start + offset
+		if !ok {
+			break
+		}
+	}
+
+	t.markToken()
+	t.token = bytes.TrimSpace(t.buf[t.start:i])
+	t.start = i
+	t.lineStart = false
+	return true
+}
+
+func (t *Tokenizer) HasNext() bool {
+	i := t.start
+	for {
+		for i < t.end && t.delims[t.buf[i]] {
+			i++
+		}
+		if i < t.end {
+			return true
+		}
+		offset := i - t.start
+		ok := t.fill()
+		i = t.start + offset
+		if !ok {
+			return false
+		}
+	}
+}
+
+func (t *Tokenizer) lineEnd() (int, bool) {
+	i := t.start
+	for {
+		for i < t.end && t.buf[i] != '\n' {
+			i++
+		}
+		if i < t.end {
+			return i, true
+		}
+		offset := i - t.start
+		ok := t.fill()
+		i = t.start + offset
+		if !ok {
+			return i, false
+		}
+	}
+}
+
+func (t *Tokenizer) AtLineEnd() bool {
+	if t.lineStart {
+		return true
+	}
+	end, _ := t.lineEnd()
+	for _, b := range t.buf[t.start:end] {
+		if !t.delims[b] {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *Tokenizer) ScanLine() bool {
+	t.mark()
+	end, found := t.lineEnd()
+	if !t.lineStart && len(bytes.TrimSpace(t.buf[t.start:end])) == 0 {
+		if !found {
+			t.start = end
+			return false
+		}
+		t.start = end + 1
+		t.line++
+		end, found = t.lineEnd()
+	}
+	if !found && end == t.start {
+		return false
+	}
+
+	t.markToken()
+	t.token = bytes.TrimSuffix(t.buf[t.start:end], []byte("\r"))
+	t.start = end
+	if found {
+		t.start++
+		t.line++
+	}
+	t.lineStart = true
+	return true
+}
+
+func (t *Tokenizer) record() {
+	t.recording = true
+	t.recordStart = t.start
+	t.recordOffset = t.base + int64(t.start)
+	t.recordLine = t.line
+	t.recordLineStart = t.lineStart
+}
+
+func (t *Tokenizer) recorded() *Tokenizer {
+	t.recording = false
+	data := append([]byte(nil), t.buf[t.recordStart:t.start]...)
+	return &Tokenizer{
+		r:         bytes.NewReader(nil),
+		buf:       data,
+		end:       len(data),
+		lineStart: t.recordLineStart,
+		delims:    t.delims,
+		base:      t.recordOffset,
+		line:      t.recordLine,
+	}
+}
+
+func (t *Tokenizer) mark() {
+	t.token = nil
+	t.canUnscan = false
+	t.prevStart = t.start
+	t.prevLine = t.line
+	t.prevLineStart = t.lineStart
+}
+
+func (t *Tokenizer) markToken() {
+	t.tokenOffset = t.base + int64(t.start)
+	t.tokenLine = t.line
+	t.canUnscan = true
+}
+
+func (t *Tokenizer) Unscan() bool {
+	if !t.canUnscan {
+		return false
+	}
+	t.token = nil
+	t.canUnscan = false
+	t.start = t.prevStart
+	t.line = t.prevLine
+	t.lineStart = t.prevLineStart
+	return true
+}
+
+func (t *Tokenizer) Position() (offset int64, line int) {
+	return t.tokenOffset, t.tokenLine
+}
+
+func (t *Tokenizer) Bytes() []byte {
+	return t.token
+}
+
+func (t *Tokenizer) Text() string {
+	return string(t.token)
+}
+
+func (t *Tokenizer) Err() error {
+	if t.err == io.EOF {
+		return nil
+	}
+	return t.err
+}
+
+func parseInt(b []byte) (int, bool) {
+	if len(b) == 0 {
+		return 0, false
+	}
+
+	negative := b[0] == '-'
+	if negative || b[0] == '+' {
+		b = b[1:]
+		if len(b) == 0 {
+			return 0, false
+		}
+	}
+
+	const maxInt = int(^uint(0) >> 1)
+	n := 0
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		d := int(c - '0')
+		if n > (maxInt-d)/10 {
+			return 0, false
+		}
+		n = n*10 + d
+	}
+
+	if negative {
+		return -n, true
+	}
+	return n, true
+}
@@ -0,0 +1,139 @@
+package io
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenizer(t *testing.T) {
+	tokenizer := newTokenizerSize(strings.NewReader("  first\tsecond\r\n  averyveryverylongtoken\n\nlast"), 4)
+
+	tokens := []string{}
+	for tokenizer.Scan() {
+		tokens = append(tokens, tokenizer.Text())
+	}
+	assert.Equal(t, []string{"first", "second", "averyveryverylongtoken", "last"}, tokens)
+	assert.NoError(t, tokenizer.Err())
+}
+
+func TestParseInt(t *testing.T) {
+	for _, str := range []string{"0", "-123", "+42", "9223372036854775807"} {
+		n, ok := parseInt([]byte(str))
+		expected, _ := strconv.Atoi(str)
+		assert.True(t, ok, str)
+		assert.Equal(t, expected, n, str)
+	}
+	for _, str := range []string{"", "-", "1a", "9223372036854775808"} {
+		_, ok := parseInt([]byte(str))
+		assert.False(t, ok, str)
+	}
+}
+
+func TestIntAllocs(t *testing.T) {
+	i := initInput(strings.Repeat("123456 -7890 ", 1000))
+	allocs := testing.AllocsPerRun(1000, func() {
+		i.Int()
+	})
+	assert.Equal(t, 0.0, allocs)
+}
+
+func TestTokenizerLine(t *testing.T) {
+	i := initInput("2 \r\nhello  world\n\n  #.#\r\n3 rest of line\nlast")
+	assert.Equal(t, 2, i.Int())
+	assert.Equal(t, "hello  world", i.Line())
+	assert.Equal(t, "", i.Line())
+	assert.Equal(t, "  #.#", i.Line())
+	assert.Equal(t, 3, i.Int())
+	assert.Equal(t, " rest of line", i.Line())
+	assert.Equal(t, "last", i.Line())
+}
+
+func TestTokenizerLineSmallBuffer(t *testing.T) {
+	i := newInput(newTokenizerSize(strings.NewReader("1\na long line that does not fit\n2"), 4))
+	assert.Equal(t, 1, i.Int())
+	assert.Equal(t, "a long line that does not fit", i.Line())
+	assert.Equal(t, 2, i.Int())
+}
+
+func TestTokenizerRecord(t *testing.T) {
+	tokenizer := newTokenizerSize(strings.NewReader("1\nfirst case\n2 second"), 4)
+	i := newInput(tokenizer)
+	assert.Equal(t, 1, i.Int())
+	tokenizer.record()
+	i.Line()
+	recorded := newInput(tokenizer.recorded())
+	assert.Equal(t, 2, i.Int())
+
+	assert.Equal(t, "first", recorded.String())
+	assert.Equal(t, "case", recorded.String())
+	offset, line := recorded.scanner.(*Tokenizer).Position()
+	assert.Equal(t, int64(8), offset)
+	assert.Equal(t, 2, line)
+	assert.False(t, recorded.HasNext())
+}
+
+func TestTokenizerDelimiters(t *testing.T) {
+	tokenizer := NewTokenizer(strings.NewReader("1,2;;3 4\n5"))
+	tokenizer.SetDelimiters(",;")
+	i := newInput(tokenizer)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, i.Ints(5))
+
+	i = initInput("a,b;c d\n 12  3\n4567")
+	assert.Equal(t, "a", i.Delimited(",;"))
+	assert.Equal(t, "b;c", i.Delimited(","))
+	assert.Equal(t, "d", i.String())
+	assert.Equal(t, "12", i.Fixed(3))
+	assert.Equal(t, "", i.Fixed(2))
+	assert.Equal(t, "3", i.Fixed(2))
+	assert.Equal(t, "45", i.Fixed(2))
+	assert.Equal(t, "67", i.Fixed(3))
+}
+
+func TestTokenizerAtLineEndDelimiters(t *testing.T) {
+	tokenizer := NewTokenizer(strings.NewReader("1,2,\n3;4\n"))
+	tokenizer.SetDelimiters(",;")
+	assert.True(t, tokenizer.Scan())
+	assert.False(t, tokenizer.AtLineEnd())
+	assert.True(t, tokenizer.Scan())
+	assert.True(t, tokenizer.AtLineEnd())
+	assert.True(t, tokenizer.Scan())
+	assert.False(t, tokenizer.AtLineEnd())
+}
+
+func TestTokenizerByteRune(t *testing.T) {
+	i := newInput(newTokenizerSize(strings.NewReader("R12 \n L-3 čx"), 4))
+	assert.Equal(t, byte('R'), i.Byte())
+	assert.Equal(t, 12, i.Int())
+	assert.Equal(t, byte('L'), i.Byte())
+	assert.Equal(t, -3, i.Int())
+	assert.Equal(t, 'č', i.Rune())
+	assert.Equal(t, 'x', i.Rune())
+}
+
+func TestTokenizerHugeToken(t *testing.T) {
+	token := strings.Repeat("x", 1<<20)
+	i := initInput("2 " + token + "\n" + token + " " + token + "\n3")
+	assert.Equal(t, 2, i.Int())
+	assert.Equal(t, token, i.String())
+	assert.Equal(t, token+" "+token, i.Line())
+	assert.Equal(t, 3, i.Int())
+}
+
+func TestTokenizerUnscan(t *testing.T) {
+	tokenizer := newTokenizerSize(strings.NewReader("first averyveryverylongtoken\nlast"), 4)
+	assert.False(t, tokenizer.Unscan())
+	assert.True(t, tokenizer.Scan())
+	assert.True(t, tokenizer.Scan())
+	assert.True(t, tokenizer.HasNext())
+	assert.True(t, tokenizer.Unscan())
+	assert.False(t, tokenizer.Unscan())
+	assert.True(t, tokenizer.ScanLine())
+	assert.Equal(t, " averyveryverylongtoken", tokenizer.Text())
+	assert.True(t, tokenizer.Scan())
+	offset, line := tokenizer.Position()
+	assert.Equal(t, int64(29), offset)
+	assert.Equal(t, 2, line)
+}
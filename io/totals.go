@@ -0,0 +1,56 @@
+package io
+
+import (
+	"bytes"
+	"log"
+	"sort"
+)
+
+type outputTotals struct {
+	tokens int
+	lines  int
+}
+
+func totals(co *CompareOutput) outputTotals {
+	t := outputTotals{}
+	for _, data := range co.outputs {
+		t.tokens += len(bytes.Fields(data))
+		for _, line := range bytes.Split(data, []byte("\n")) {
+			if len(bytes.TrimSpace(line)) > 0 {
+				t.lines++
+			}
+		}
+	}
+	return t
+}
+
+func missingCases(co, other *CompareOutput) []int {
+	cases := []int{}
+	for caseN := range co.outputs {
+		if !other.HasOutput(caseN) {
+			cases = append(cases, caseN)
+		}
+	}
+	sort.Ints(cases)
+	return cases
+}
+
+func (parser *Parser) checkTotals() {
+	if parser.compareOutput == nil || parser.cases != nil || parser.shardCount > 0 || parser.failedOnly {
+		return
+	}
+	produced := parser.openOutput(parser.outputFn)
+	if produced == nil {
+		return
+	}
+
+	if missing := missingCases(parser.compareOutput, produced); len(missing) > 0 {
+		log.Printf("%s: cases %v of %s are missing in output\n", parser.inputFn, missing, parser.correctFn)
+	}
+	if extra := missingCases(produced, parser.compareOutput); len(extra) > 0 {
+		log.Printf("%s: output has cases %v that are not in %s\n", parser.inputFn, extra, parser.correctFn)
+	}
+	if p, c := totals(produced), totals(parser.compareOutput); p != c {
+		log.Printf("%s: output has %d tokens in %d lines, %s has %d tokens in %d lines\n", parser.inputFn, p.tokens, p.lines, parser.correctFn, c.tokens, c.lines)
+	}
+}
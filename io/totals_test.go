@@ -0,0 +1,28 @@
+package io
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckTotals(t *testing.T) {
+	dir := testDir(t)
+	defer os.RemoveAll(dir)
+	logs := &bytes.Buffer{}
+	log.SetOutput(logs)
+	defer log.SetOutput(os.Stderr)
+
+	inputFn := filepath.Join(dir, "A.in")
+	writeTestFile(t, inputFn, "2\n1\n2\n")
+	writeTestFile(t, filepath.Join(dir, "A.correct"), "Case #1: 2\nCase #2: 4 trailing\nCase #3: 6\n")
+
+	parser := newParser(double, WithArgs(inputFn), WithNoProfile())
+	parser.Run()
+	assert.Contains(t, logs.String(), "cases [3] of "+filepath.Join(dir, "A.correct")+" are missing in output")
+	assert.Contains(t, logs.String(), "output has 2 tokens in 2 lines, "+filepath.Join(dir, "A.correct")+" has 4 tokens in 3 lines")
+}
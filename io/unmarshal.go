@@ -0,0 +1,135 @@
+package io
+
+import (
+	"log"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var bigIntType = reflect.TypeOf((*big.Int)(nil))
+
+type fieldTag struct {
+	name   string
+	length string
+}
+
+func parseFieldTag(field reflect.StructField) (fieldTag, bool) {
+	tag := field.Tag.Get("cj")
+	if tag == "-" {
+		return fieldTag{}, false
+	}
+
+	ft := fieldTag{name: field.Name}
+	for j, part := range strings.Split(tag, ",") {
+		if j == 0 {
+			if part != "" {
+				ft.name = part
+			}
+			continue
+		}
+		if strings.HasPrefix(part, "len=") {
+			ft.length = strings.TrimPrefix(part, "len=")
+			continue
+		}
+		log.Fatalf("Invalid cj tag option %q on field %s\n", part, field.Name)
+	}
+	return ft, true
+}
+
+func (i *Input) Read(v interface{}) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		log.Fatalln("Read needs a non-nil pointer, got", rv.Type())
+	}
+	i.readValue(rv.Elem(), "")
+}
+
+func (i *Input) readStruct(rv reflect.Value) {
+	ints := make(map[string]int)
+	rt := rv.Type()
+	for j := 0; j < rt.NumField(); j++ {
+		field := rt.Field(j)
+		if field.PkgPath != "" {
+			continue
+		}
+		ft, ok := parseFieldTag(field)
+		if !ok {
+			continue
+		}
+
+		length := -1
+		if ft.length != "" {
+			n, err := strconv.Atoi(ft.length)
+			if err != nil {
+				var ok bool
+				n, ok = ints[ft.length]
+				if !ok {
+					log.Fatalf("Length %q of field %s is not a number or an earlier int field\n", ft.length, field.Name)
+				}
+			}
+			length = n
+		}
+
+		fv := rv.Field(j)
+		i.readSliceOrValue(fv, length, field.Name)
+		switch fv.Kind() {
+		case reflect.Int, reflect.Int64:
+			ints[ft.name] = int(fv.Int())
+		}
+	}
+}
+
+func (i *Input) readSliceOrValue(rv reflect.Value, length int, name string) {
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+		if length >= 0 {
+			log.Fatalf("Field %s has len but is not a slice\n", name)
+		}
+		i.readValue(rv, name)
+		return
+	}
+	if length < 0 {
+		log.Fatalf("Slice field %s needs len in cj tag\n", name)
+	}
+
+	slice := reflect.MakeSlice(rv.Type(), length, length)
+	for j := 0; j < length; j++ {
+		i.readValue(slice.Index(j), name)
+	}
+	rv.Set(slice)
+}
+
+func (i *Input) readValue(rv reflect.Value, name string) {
+	if rv.Type() == bigIntType {
+		rv.Set(reflect.ValueOf(i.BigInt()))
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		rv.SetInt(i.Int64())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		rv.SetUint(i.Uint64())
+	case reflect.Float32, reflect.Float64:
+		rv.SetFloat(i.Float())
+	case reflect.String:
+		rv.SetString(i.String())
+	case reflect.Bool:
+		rv.SetBool(i.Int() != 0)
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() != reflect.Uint8 {
+			log.Fatalf("Slice %s needs len in cj tag of its struct field\n", name)
+		}
+		rv.SetBytes(i.Bytes())
+	case reflect.Struct:
+		i.readStruct(rv)
+	case reflect.Ptr:
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		i.readValue(rv.Elem(), name)
+	default:
+		log.Fatalf("Unsupported type %s of %s\n", rv.Type(), name)
+	}
+}
@@ -0,0 +1,43 @@
+package io
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testEdge struct {
+	From, To int
+	Weight   float64
+}
+
+type testCase struct {
+	N      int        `cj:"n"`
+	M      int        `cj:"m"`
+	Name   string     `cj:"name"`
+	A      []int      `cj:"a,len=n"`
+	Edges  []testEdge `cj:",len=m"`
+	Pair   [2]int     `cj:"-"`
+	Big    *big.Int
+	Grid   [][]byte `cj:",len=2"`
+	hidden int
+}
+
+func TestRead(t *testing.T) {
+	i := initInput("3 1 alice\n1 2 3\n1 2 0.5\n123456789012345678901234567890\n#.\n.#\n")
+
+	var tc testCase
+	i.Read(&tc)
+
+	big, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	assert.Equal(t, testCase{
+		N:     3,
+		M:     1,
+		Name:  "alice",
+		A:     []int{1, 2, 3},
+		Edges: []testEdge{{From: 1, To: 2, Weight: 0.5}},
+		Big:   big,
+		Grid:  [][]byte{[]byte("#."), []byte(".#")},
+	}, tc)
+}
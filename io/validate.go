@@ -0,0 +1,78 @@
+package io
+
+import (
+	"io"
+	"log"
+	"os"
+)
+
+type ValidateFunc func(*Input)
+
+func (i *Input) IntIn(lo, hi int) int {
+	n := i.Int()
+	if n < lo || n > hi {
+		log.Fatalf("Token %d: %d not in [%d, %d]\n", i.position, n, lo, hi)
+	}
+	return n
+}
+
+func (i *Input) FloatIn(lo, hi float64) float64 {
+	f := i.Float()
+	if f < lo || f > hi {
+		log.Fatalf("Token %d: %g not in [%g, %g]\n", i.position, f, lo, hi)
+	}
+	return f
+}
+
+func (i *Input) SliceIntIn(n, lo, hi int) []int {
+	ints := make([]int, 0, n)
+	for j := 0; j < n; j++ {
+		ints = append(ints, i.IntIn(lo, hi))
+	}
+	return ints
+}
+
+func (parser *Parser) validate(r io.Reader) int {
+	scanner := NewTokenizer(r)
+	scanner.SetDelimiters(parser.delimiters)
+	input := newInput(scanner)
+
+	T := -1
+	if parser.format == "" || parser.format == FormatCases {
+		T = input.IntIn(1, int(^uint(0)>>1))
+	} else {
+		T = parser.readCaseCount(input)
+	}
+	i := 1
+	for ; moreCases(input, i, T); i++ {
+		input.init(parser.caseSeed(i))
+		parser.validator(input)
+	}
+
+	if scanner.Scan() {
+		log.Fatalf("Token %d: unexpected %q after case %d\n", input.position+1, scanner.Text(), i-1)
+	}
+	return i - 1
+}
+
+func (parser *Parser) validateFiles(inputFns []string) {
+	if parser.validator == nil {
+		log.Fatalln("No validator, use io.WithValidator")
+	}
+
+	if len(inputFns) == 0 {
+		T := parser.validate(os.Stdin)
+		log.Printf("stdin: %d cases valid\n", T)
+		return
+	}
+
+	for _, inputFn := range inputFns {
+		f, err := openInput(inputFn)
+		if err != nil {
+			log.Fatalln("Error opening input file:", err)
+		}
+		T := parser.validate(f)
+		f.Close()
+		log.Printf("%s: %d cases valid\n", inputFn, T)
+	}
+}
@@ -0,0 +1,35 @@
+package io
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntIn(t *testing.T) {
+	i := initInput("1 5 10")
+	assert.Equal(t, 1, i.IntIn(1, 10))
+	assert.Equal(t, []int{5, 10}, i.SliceIntIn(2, 5, 10))
+	assert.Equal(t, int64(3), i.position)
+}
+
+func TestValidate(t *testing.T) {
+	validator := func(input *Input) {
+		n := input.IntIn(1, 3)
+		input.SliceIntIn(n, -5, 5)
+	}
+	parser := newParser(double, WithValidator(validator))
+	assert.Equal(t, 2, parser.validate(strings.NewReader("2\n1 5\n3 -5 0 1\n")))
+}
+
+func TestValidateSeed(t *testing.T) {
+	seeds := []int64{}
+	parser := newParser(double, WithValidator(func(input *Input) {
+		input.Int()
+		seeds = append(seeds, input.seed)
+	}))
+	parser.seed = 40
+	parser.validate(strings.NewReader("2\n1\n2\n"))
+	assert.Equal(t, []int64{parser.caseSeed(1), parser.caseSeed(2)}, seeds)
+}
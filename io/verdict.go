@@ -0,0 +1,126 @@
+package io
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+	"time"
+)
+
+type Checker func(input, produced, correct string) bool
+
+type Verdict int
+
+const (
+	Unchecked Verdict = iota
+	OK
+	WA
+	TLE
+	RE
+	MLE
+)
+
+var verdictNames = []string{"-", "OK", "WA", "TLE", "RE", "MLE"}
+
+func (v Verdict) String() string {
+	return verdictNames[v]
+}
+
+func (v Verdict) failed() bool {
+	return v == WA || v == TLE || v == RE || v == MLE
+}
+
+type caseResult struct {
+	caseN    int
+	verdict  Verdict
+	duration time.Duration
+	memory   uint64
+	count    int
+	tokens   int64
+}
+
+func countVerdicts(results []caseResult) []int {
+	counts := make([]int, len(verdictNames))
+	for _, r := range results {
+		counts[r.verdict]++
+	}
+	return counts
+}
+
+func formatVerdicts(results []caseResult) string {
+	buffer := &bytes.Buffer{}
+	for v, count := range countVerdicts(results) {
+		if count == 0 || Verdict(v) == Unchecked {
+			continue
+		}
+		if buffer.Len() != 0 {
+			buffer.WriteString(", ")
+		}
+		fmt.Fprintf(buffer, "%d %s", count, Verdict(v))
+	}
+	return buffer.String()
+}
+
+func (parser *Parser) logVerdicts() {
+	verdicts := formatVerdicts(parser.results)
+	if verdicts == "" {
+		return
+	}
+	log.Println("Verdicts:", verdicts)
+}
+
+func (parser *Parser) equal(input, produced, correct string) bool {
+	produced = strings.TrimSpace(parser.normalize(produced))
+	correct = strings.TrimSpace(parser.normalize(correct))
+	if parser.anyLineOrder {
+		produced = sortLines(produced)
+		correct = sortLines(correct)
+	}
+	if parser.checker != nil {
+		return parser.checker(input, produced, correct)
+	}
+	if parser.absTolerance > 0 || parser.relTolerance > 0 {
+		return tokensEqual(produced, correct, parser.absTolerance, parser.relTolerance)
+	}
+	return produced == correct
+}
+
+func (parser *Parser) checkWholeOutput() {
+	correct, err := ioutil.ReadFile(parser.correctFn)
+	if err != nil {
+		return
+	}
+	produced, err := ioutil.ReadFile(parser.outputFn)
+	if err != nil {
+		log.Fatalln("Error reading output file:", err)
+	}
+	if parser.equal("", string(produced), string(correct)) {
+		log.Println("Output matches", parser.correctFn)
+		return
+	}
+	log.Println("Output does not match", parser.correctFn)
+	if parser.summary != nil {
+		parser.summary.addFailedFile(parser.inputFn)
+	}
+}
+
+func (parser *Parser) check(input *Input, output *Output, correct string) Verdict {
+	if parser.equal(strings.Join(input.currentCase(), " "), string(output.output.Bytes()), correct) {
+		return OK
+	}
+	if parser.checker != nil {
+		output.Debugf("Checker rejected output, correct: %q", correct)
+	} else {
+		output.Debug(output.diff(correct, parser.sameToken))
+	}
+	return WA
+}
+
+func (parser *Parser) sameToken(p, c string) bool {
+	if parser.absTolerance > 0 || parser.relTolerance > 0 {
+		return tokensEqual(p, c, parser.absTolerance, parser.relTolerance)
+	}
+	return p == c
+}
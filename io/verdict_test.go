@@ -0,0 +1,37 @@
+package io
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatVerdicts(t *testing.T) {
+	results := []caseResult{
+		{caseN: 1, verdict: OK},
+		{caseN: 2, verdict: TLE},
+		{caseN: 3, verdict: OK},
+		{caseN: 4, verdict: Unchecked},
+	}
+	assert.Equal(t, "2 OK, 1 TLE", formatVerdicts(results))
+	assert.Equal(t, "", formatVerdicts([]caseResult{{caseN: 1}}))
+	assert.Equal(t, "WA", WA.String())
+}
+
+func TestParserEqual(t *testing.T) {
+	parser := newParser(double)
+	assert.True(t, parser.equal("", " 1.5\n", "1.5"))
+	assert.False(t, parser.equal("", "1.5000001", "1.5"))
+
+	WithFloatTolerance(1e-6, 1e-6)(parser)
+	assert.True(t, parser.equal("", "1.5000001", "1.5"))
+}
+
+func TestParserEqualAnyLineOrder(t *testing.T) {
+	parser := newParser(double)
+	assert.False(t, parser.equal("", "2\na\nb", "2\nb\na"))
+
+	WithAnyLineOrder()(parser)
+	assert.True(t, parser.equal("", "2\na\nb", "2\nb\na"))
+	assert.False(t, parser.equal("", "2\na\na", "2\nb\na"))
+}